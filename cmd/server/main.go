@@ -2,28 +2,105 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"flag"
 	"log"
-	"math/rand"
 	"monopoly/internal/api/handler"
+	"monopoly/internal/audit"
+	"monopoly/internal/auth"
+	"monopoly/internal/game"
+	"monopoly/internal/ledger"
 	"monopoly/internal/manager"
+	"monopoly/internal/storage"
 	"monopoly/internal/user"
+	"monopoly/pkg/utils"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gorilla/mux"
 )
 
 func main() {
-	// 初始化随机数种子
-	rand.Seed(time.Now().UnixNano())
+	mapsDir := flag.String("maps", "maps", "directory containing map definition files")
+	dataDir := flag.String("data", "", "directory for persistent data; keeps everything in memory when empty")
+	snapshotEvery := flag.Int("snapshot-every", storage.DefaultSnapshotEvery, "number of log records between snapshots")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 15*time.Second, "how long to wait for in-flight requests on shutdown")
+	authSecret := flag.String("auth-secret", os.Getenv("MONOPOLY_AUTH_SECRET"), "secret for signing session tokens; a random one is generated when empty")
+	tokenTTL := flag.Duration("token-ttl", 24*time.Hour, "how long issued session tokens stay valid")
+	adminID := flag.String("admin", "", "user granted the admin role at startup; created with the MONOPOLY_ADMIN_PASSWORD password if missing")
+	flag.Parse()
 
-	// 初始化依赖
-	userManager := user.NewManager()
-	gameManager := manager.NewGameManager()
+	// 加载并验证地图
+	maps, err := game.LoadMaps(*mapsDir)
+	if err != nil {
+		log.Fatalf("load maps: %v", err)
+	}
+
+	// 打开存储
+	store, err := openStore(*dataDir, *snapshotEvery)
+	if err != nil {
+		log.Fatalf("open store: %v", err)
+	}
+
+	// 初始化依赖并从存储恢复数据
+	coinLedger := ledger.NewLedger(store)
+	userManager := user.NewManager(coinLedger, store)
+	credentials := auth.NewCredentials(store)
+	auditLog := audit.NewLog(store)
+	gameManager := manager.NewGameManager(maps, store)
+	if err := coinLedger.Load(); err != nil {
+		log.Fatalf("load ledger: %v", err)
+	}
+	if err := userManager.Load(); err != nil {
+		log.Fatalf("load users: %v", err)
+	}
+	if err := userManager.RetryPendingSettlements(); err != nil {
+		// 未完成的结算保留到下次启动继续重试，账本检查会报告这些游戏
+		log.Printf("retry pending settlements: %v", err)
+	}
+	if err := credentials.Load(); err != nil {
+		log.Fatalf("load credentials: %v", err)
+	}
+	if err := auditLog.Load(); err != nil {
+		log.Fatalf("load audit log: %v", err)
+	}
+	if err := gameManager.Load(); err != nil {
+		log.Fatalf("load games: %v", err)
+	}
+	if *adminID != "" {
+		if err := bootstrapAdmin(userManager, credentials, auditLog, *adminID, os.Getenv("MONOPOLY_ADMIN_PASSWORD")); err != nil {
+			log.Fatalf("bootstrap admin: %v", err)
+		}
+	}
+	manager.NewLedgerRecorder(gameManager, coinLedger) // 需先于结算订阅游戏事件
+	settlement := manager.NewSettlement(gameManager, userManager)
+
+	// 上次停机时暂停的游戏顺延停机时长后继续
+	if resumed := gameManager.ResumePaused(); resumed > 0 {
+		log.Printf("Resumed %d paused games", resumed)
+	}
+	gameManager.Scheduler().Start()
+
+	// 令牌签发器
+	secret, err := tokenSecret(*authSecret)
+	if err != nil {
+		log.Fatalf("token secret: %v", err)
+	}
+	issuer := auth.NewIssuer(secret, *tokenTTL)
 
 	// 初始化处理器
-	userHandler := handler.NewUserHandler(userManager)
-	gameHandler := handler.NewGameHandler(gameManager, userManager)
+	authHandler := handler.NewAuthHandler(issuer, credentials, userManager)
+	userHandler := handler.NewUserHandler(userManager, credentials)
+	gameHandler := handler.NewGameHandler(gameManager, settlement, userManager)
+	mapHandler := handler.NewMapHandler(maps)
+	ledgerHandler := handler.NewLedgerHandler(coinLedger)
+	adminHandler := handler.NewAdminHandler(userManager, credentials, gameManager, coinLedger, auditLog)
+	wsHandler := handler.NewWebSocketHandler(gameManager)
 
 	// 创建路由器
 	r := mux.NewRouter()
@@ -31,33 +108,80 @@ func main() {
 	// API 路由
 	apiRouter := r.PathPrefix("/api").Subrouter()
 
+	// 认证相关路由
+	apiRouter.HandleFunc("/auth/token", authHandler.IssueToken).Methods("POST")
+
 	// 用户相关路由
 	apiRouter.HandleFunc("/users", userHandler.Create).Methods("POST")
 	apiRouter.HandleFunc("/users/{userId}", userHandler.Get).Methods("GET")
 	apiRouter.HandleFunc("/users/{userId}", userHandler.Update).Methods("PUT")
 	apiRouter.HandleFunc("/users/{userId}", userHandler.Delete).Methods("DELETE")
-	apiRouter.HandleFunc("/users/{userId}/coins", userHandler.AddCoins).Methods("POST")
-	apiRouter.HandleFunc("/users/{userId}/coins/deduct", userHandler.DeductCoins).Methods("POST")
 	apiRouter.HandleFunc("/users/{userId}/transactions", userHandler.GetUserTransactions).Methods("GET")
 	apiRouter.HandleFunc("/users/{userId}/games", userHandler.GetUserGames).Methods("GET")
 	apiRouter.HandleFunc("/users/{userId}/balance", userHandler.CheckUserBalance).Methods("GET")
 
+	// 地图相关路由
+	apiRouter.HandleFunc("/maps", mapHandler.List).Methods("GET")
+
 	// 游戏相关路由
 	apiRouter.HandleFunc("/games", gameHandler.Create).Methods("POST")
 	apiRouter.HandleFunc("/games/{gameId}", gameHandler.Get).Methods("GET")
 	apiRouter.HandleFunc("/games/{gameId}/join", gameHandler.Join).Methods("POST")
 	apiRouter.HandleFunc("/games/{gameId}/start", gameHandler.StartGame).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/settings", gameHandler.UpdateSettings).Methods("PUT")
+	apiRouter.HandleFunc("/games/{gameId}/pause", gameHandler.PauseGame).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/resume", gameHandler.ResumeGame).Methods("POST")
 	apiRouter.HandleFunc("/games/{gameId}/roll", gameHandler.RollDice).Methods("POST")
 	apiRouter.HandleFunc("/games/{gameId}/properties/{position}/buy", gameHandler.BuyProperty).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/properties/{position}/decline", gameHandler.DeclineProperty).Methods("POST")
 	apiRouter.HandleFunc("/games/{gameId}/properties/{position}/upgrade", gameHandler.UpgradeProperty).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/properties/{position}/sell-upgrade", gameHandler.SellUpgrade).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/properties/{position}/mortgage", gameHandler.MortgageProperty).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/properties/{position}/unmortgage", gameHandler.UnmortgageProperty).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/debt/pay", gameHandler.PayDebt).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/bankruptcy", gameHandler.DeclareBankruptcy).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/prison/bail", gameHandler.PayBail).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/prison/card", gameHandler.UsePrisonCard).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/bank/loan", gameHandler.TakeLoan).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/bank/repay", gameHandler.RepayLoan).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/bank/deposit", gameHandler.Deposit).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/bank/withdraw", gameHandler.Withdraw).Methods("POST")
 	apiRouter.HandleFunc("/games/{gameId}/end-turn", gameHandler.EndTurn).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/auction", gameHandler.GetAuction).Methods("GET")
+	apiRouter.HandleFunc("/games/{gameId}/auction/bid", gameHandler.PlaceBid).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/trades", gameHandler.ListTrades).Methods("GET")
+	apiRouter.HandleFunc("/games/{gameId}/trades", gameHandler.ProposeTrade).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/trades/{tradeId}/accept", gameHandler.AcceptTrade).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/trades/{tradeId}/reject", gameHandler.RejectTrade).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/trades/{tradeId}/counter", gameHandler.CounterTrade).Methods("POST")
 	apiRouter.HandleFunc("/games/{gameId}/status", gameHandler.GetGameStatus).Methods("GET")
 	apiRouter.HandleFunc("/games/{gameId}/players/{playerId}", gameHandler.GetPlayerStatus).Methods("GET")
 	apiRouter.HandleFunc("/games/{gameId}/players/{playerId}/leave", gameHandler.LeaveGame).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/players/{playerId}/kick", gameHandler.KickPlayer).Methods("POST")
+	apiRouter.HandleFunc("/games/{gameId}/replay", gameHandler.Replay).Methods("GET")
+	apiRouter.HandleFunc("/games/{gameId}/ws", wsHandler.Connect).Methods("GET")
+	apiRouter.HandleFunc("/games/{gameId}/events", gameHandler.Events).Methods("GET")
+
+	// 账本相关路由
+	apiRouter.HandleFunc("/ledger/accounts/{account}/entries", ledgerHandler.GetEntries).Methods("GET")
+
+	// 管理相关路由，只对管理员开放
+	adminRouter := apiRouter.PathPrefix("/admin").Subrouter()
+	adminRouter.HandleFunc("/users/{userId}/coins/mint", adminHandler.MintCoins).Methods("POST")
+	adminRouter.HandleFunc("/users/{userId}/coins/burn", adminHandler.BurnCoins).Methods("POST")
+	adminRouter.HandleFunc("/users/{userId}/role", adminHandler.SetRole).Methods("PUT")
+	adminRouter.HandleFunc("/users/{userId}", adminHandler.DeleteUser).Methods("DELETE")
+	adminRouter.HandleFunc("/games/{gameId}/end", adminHandler.EndGame).Methods("POST")
+	adminRouter.HandleFunc("/games/{gameId}/players/{playerId}/kick", adminHandler.KickPlayer).Methods("POST")
+	adminRouter.HandleFunc("/ledger/accounts/{account}/entries", adminHandler.GetLedgerEntries).Methods("GET")
+	adminRouter.HandleFunc("/ledger/check", adminHandler.CheckLedger).Methods("GET")
+	adminRouter.HandleFunc("/audit", adminHandler.GetAuditLog).Methods("GET")
+	adminRouter.Use(authHandler.RequireAdmin)
 
 	// 中间件
 	apiRouter.Use(loggingMiddleware)
 	apiRouter.Use(recoveryMiddleware)
+	apiRouter.Use(authHandler.Authenticate)
 
 	// 服务器配置
 	server := &http.Server{
@@ -68,9 +192,110 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// 停机开始时断开 WebSocket 和 SSE 推送连接，等待处理中的请求时不必等待这些长连接
+	server.RegisterOnShutdown(gameManager.Broadcaster().Close)
+
+	// 收到 SIGINT 或 SIGTERM 时停止接收请求
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// 启动服务器
-	log.Printf("Server starting on %s", server.Addr)
-	log.Fatal(server.ListenAndServe())
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Server starting on %s", server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	case <-ctx.Done():
+		log.Printf("Shutting down")
+	}
+
+	shutdown(server, gameManager, store, *shutdownTimeout)
+}
+
+// shutdown 优雅停机：等待处理中的请求完成（最长 timeout），暂停所有进行中的游戏，
+// 再关闭存储写入检查点，重启后暂停的游戏保留剩余的回合时间继续进行
+func shutdown(server *http.Server, gameManager *manager.GameManager, store storage.Store, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("drain requests: %v", err)
+	}
+
+	gameManager.Shutdown()
+
+	if err := store.Close(); err != nil {
+		log.Printf("checkpoint: %v", err)
+		return
+	}
+	log.Printf("Server stopped")
+}
+
+// tokenSecret 获取令牌签名密钥，未配置时随机生成，重启后之前签发的令牌失效
+func tokenSecret(configured string) ([]byte, error) {
+	if configured != "" {
+		return []byte(configured), nil
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	log.Printf("No -auth-secret configured, using a random one; tokens will not survive a restart")
+	return secret, nil
+}
+
+// bootstrapAdmin 授予用户管理员角色，用户不存在时使用 password 创建，用于首次部署时产生第一个管理员
+// 角色变化时以 system 为操作者写入审计日志
+func bootstrapAdmin(um *user.Manager, credentials *auth.Credentials, auditLog *audit.Log, userID, password string) error {
+	u, err := um.GetUser(userID)
+	if errors.Is(err, utils.ErrUserNotFound) {
+		if password == "" {
+			return errors.New("MONOPOLY_ADMIN_PASSWORD is required to create the admin user")
+		}
+		if err := auth.ValidatePassword(password); err != nil {
+			return err
+		}
+		u = &user.User{ID: userID, Name: userID}
+		if err := um.CreateUser(u); err != nil {
+			return err
+		}
+		if err := credentials.SetPassword(userID, password); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if u.Role == user.RoleAdmin {
+		return nil
+	}
+	previous := u.Role
+	if err := um.SetRole(userID, user.RoleAdmin); err != nil {
+		return err
+	}
+	_, err = auditLog.Record(audit.Entry{
+		Actor:   audit.ActorSystem,
+		Action:  audit.ActionSetRole,
+		Target:  userID,
+		Reason:  "-admin flag",
+		Details: map[string]interface{}{"from": previous, "to": user.RoleAdmin},
+	})
+	return err
+}
+
+// openStore 打开数据目录中的文件存储，未指定数据目录时使用内存存储
+func openStore(dir string, snapshotEvery int) (storage.Store, error) {
+	if dir == "" {
+		return storage.NewMemoryStore(), nil
+	}
+	return storage.OpenFileStore(dir, snapshotEvery)
 }
 
 // loggingMiddleware 日志中间件
@@ -2,4 +2,8 @@ module monopoly
 
 go 1.23.3
 
-require github.com/gorilla/mux v1.8.1
\ No newline at end of file
+require (
+	github.com/gorilla/mux v1.8.1
+	github.com/gorilla/websocket v1.5.3
+	golang.org/x/crypto v0.40.0
+)
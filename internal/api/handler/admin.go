@@ -0,0 +1,329 @@
+// internal/api/handler/admin.go
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"monopoly/internal/api/response"
+	"monopoly/internal/audit"
+	"monopoly/internal/auth"
+	"monopoly/internal/ledger"
+	"monopoly/internal/manager"
+	"monopoly/internal/user"
+	"monopoly/pkg/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// errReasonRequired 管理操作未说明原因
+var errReasonRequired = fmt.Errorf("%w: reason is required", utils.ErrInvalidInput)
+
+// AdminHandler 管理接口的HTTP请求处理器，路由需经 AuthHandler.RequireAdmin 保护
+// 每个管理操作都必须说明原因，执行前写入审计日志
+type AdminHandler struct {
+	userManager *user.Manager
+	credentials *auth.Credentials
+	gameManager *manager.GameManager
+	ledger      *ledger.Ledger
+	audit       *audit.Log
+}
+
+// NewAdminHandler 创建新的管理处理器
+func NewAdminHandler(um *user.Manager, credentials *auth.Credentials, gm *manager.GameManager, l *ledger.Ledger, auditLog *audit.Log) *AdminHandler {
+	return &AdminHandler{
+		userManager: um,
+		credentials: credentials,
+		gameManager: gm,
+		ledger:      l,
+		audit:       auditLog,
+	}
+}
+
+// adminRequest 管理操作的请求体
+type adminRequest struct {
+	Amount int    `json:"amount"`
+	Role   string `json:"role"`
+	Reason string `json:"reason"` // 操作原因，必填，记入审计日志
+}
+
+// MintCoins 为用户增发游戏币
+func (h *AdminHandler) MintCoins(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := vars["userId"]
+
+	req, err := decodeAdminRequest(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	h.perform(w, r, audit.Entry{
+		Action:  audit.ActionMintCoins,
+		Target:  userID,
+		Reason:  req.Reason,
+		Details: map[string]interface{}{"amount": req.Amount},
+	}, "user", func() (interface{}, error) {
+		if err := h.userManager.AddCoins(userID, req.Amount); err != nil {
+			return nil, err
+		}
+		return h.userManager.GetUser(userID)
+	})
+}
+
+// BurnCoins 销毁用户的游戏币
+func (h *AdminHandler) BurnCoins(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := vars["userId"]
+
+	req, err := decodeAdminRequest(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	h.perform(w, r, audit.Entry{
+		Action:  audit.ActionBurnCoins,
+		Target:  userID,
+		Reason:  req.Reason,
+		Details: map[string]interface{}{"amount": req.Amount},
+	}, "user", func() (interface{}, error) {
+		if err := h.userManager.DeductCoins(userID, req.Amount); err != nil {
+			return nil, err
+		}
+		return h.userManager.GetUser(userID)
+	})
+}
+
+// SetRole 修改用户角色，管理员不能修改自己的角色，避免失去最后一个管理员
+func (h *AdminHandler) SetRole(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := vars["userId"]
+
+	req, err := decodeAdminRequest(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	actor, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+	if actor == userID {
+		response.JsonError(w, utils.ErrForbidden)
+		return
+	}
+
+	u, err := h.userManager.GetUser(userID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	h.perform(w, r, audit.Entry{
+		Action:  audit.ActionSetRole,
+		Target:  userID,
+		Reason:  req.Reason,
+		Details: map[string]interface{}{"from": u.Role, "to": req.Role},
+	}, "user", func() (interface{}, error) {
+		if err := h.userManager.SetRole(userID, req.Role); err != nil {
+			return nil, err
+		}
+		return h.userManager.GetUser(userID)
+	})
+}
+
+// DeleteUser 删除用户及其登录密码，剩余余额转出系统
+func (h *AdminHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := vars["userId"]
+
+	req, err := decodeAdminRequest(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	u, err := h.userManager.GetUser(userID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	h.perform(w, r, audit.Entry{
+		Action:  audit.ActionDeleteUser,
+		Target:  userID,
+		Reason:  req.Reason,
+		Details: map[string]interface{}{"coins": u.Coins},
+	}, "userId", func() (interface{}, error) {
+		if err := h.userManager.DeleteUser(userID); err != nil {
+			return nil, err
+		}
+		if err := h.credentials.Delete(userID); err != nil {
+			return nil, err
+		}
+		return userID, nil
+	})
+}
+
+// EndGame 强制结束游戏：等待开始的游戏被放弃并退还买入金额，进行中或暂停的游戏立即结算奖池
+func (h *AdminHandler) EndGame(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	req, err := decodeAdminRequest(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	h.perform(w, r, audit.Entry{
+		Action: audit.ActionEndGame,
+		Target: gameID,
+		Reason: req.Reason,
+	}, "game", func() (interface{}, error) {
+		if err := g.ForceEnd(); err != nil {
+			return nil, err
+		}
+		return snapshotGame(g)
+	})
+}
+
+// KickPlayer 将玩家踢出游戏，等待状态下退还买入金额，进行中的游戏按破产处理
+func (h *AdminHandler) KickPlayer(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+	playerID := vars["playerId"]
+
+	req, err := decodeAdminRequest(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	h.perform(w, r, audit.Entry{
+		Action:  audit.ActionKickPlayer,
+		Target:  gameID,
+		Reason:  req.Reason,
+		Details: map[string]interface{}{"playerId": playerID},
+	}, "action", func() (interface{}, error) {
+		return g.KickPlayer(playerID)
+	})
+}
+
+// GetLedgerEntries 查询任意账户的余额和账本分录，原因通过 reason 查询参数说明
+func (h *AdminHandler) GetLedgerEntries(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	account := ledger.Account(vars["account"])
+
+	if _, err := h.record(r, audit.Entry{
+		Action: audit.ActionViewLedger,
+		Target: string(account),
+		Reason: r.URL.Query().Get("reason"),
+	}); err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(ledgerEntries(h.ledger, account)))
+}
+
+// CheckLedger 检查账本一致性，确认没有金币凭空产生或消失，原因通过 reason 查询参数说明
+func (h *AdminHandler) CheckLedger(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.record(r, audit.Entry{
+		Action: audit.ActionCheckLedger,
+		Reason: r.URL.Query().Get("reason"),
+	}); err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	report, err := manager.CheckLedger(h.gameManager, h.userManager, h.ledger)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(report))
+}
+
+// GetAuditLog 查询审计日志，可按 actor、action、target 查询参数筛选
+func (h *AdminHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	entries := h.audit.Entries(audit.Filter{
+		Actor:  query.Get("actor"),
+		Action: query.Get("action"),
+		Target: query.Get("target"),
+	})
+
+	response.JSON(w, http.StatusOK, response.Success(map[string]interface{}{
+		"entries": entries,
+	}))
+}
+
+// record 以令牌对应的管理员为操作者写入审计日志，未说明原因时返回错误
+func (h *AdminHandler) record(r *http.Request, entry audit.Entry) (audit.Entry, error) {
+	if strings.TrimSpace(entry.Reason) == "" {
+		return audit.Entry{}, errReasonRequired
+	}
+
+	actor, err := actingUser(r)
+	if err != nil {
+		return audit.Entry{}, err
+	}
+	entry.Actor = actor
+	return h.audit.Record(entry)
+}
+
+// perform 先写入审计记录再执行操作，返回操作结果和对应的审计记录
+// 审计记录写入失败时不执行操作，客户端可以安全重试；操作失败时追加一条失败记录
+func (h *AdminHandler) perform(w http.ResponseWriter, r *http.Request, entry audit.Entry, key string, apply func() (interface{}, error)) {
+	recorded, err := h.record(r, entry)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	result, err := apply()
+	if err != nil {
+		if _, auditErr := h.audit.RecordFailure(recorded, err); auditErr != nil {
+			log.Printf("audit: record failure of entry %d: %v", recorded.ID, auditErr)
+		}
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(map[string]interface{}{
+		key:     result,
+		"audit": recorded,
+	}))
+}
+
+// decodeAdminRequest 解析管理操作的请求体，操作原因不能为空
+func decodeAdminRequest(r *http.Request) (*adminRequest, error) {
+	var req adminRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, utils.ErrInvalidInput
+	}
+
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, errReasonRequired
+	}
+	return &req, nil
+}
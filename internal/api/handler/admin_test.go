@@ -0,0 +1,153 @@
+// internal/api/handler/admin_test.go
+package handler
+
+import (
+	"errors"
+	"monopoly/internal/audit"
+	"monopoly/internal/auth"
+	"monopoly/internal/game"
+	"monopoly/internal/ledger"
+	"monopoly/internal/manager"
+	"monopoly/internal/storage"
+	"monopoly/internal/user"
+	"net/http"
+	"testing"
+)
+
+// auditStore 可以模拟审计日志写入失败的存储
+type auditStore struct {
+	storage.Store
+	down bool
+}
+
+func (s *auditStore) Put(collection, key string, value interface{}) error {
+	if s.down && collection == storage.CollectionAudit {
+		return errors.New("audit store unavailable")
+	}
+	return s.Store.Put(collection, key, value)
+}
+
+// newAdminFixture 创建管理处理器，用户 alice 有1000金币，root 是管理员
+func newAdminFixture(t *testing.T) (*AdminHandler, *auditStore) {
+	t.Helper()
+
+	maps, err := game.LoadMaps("../../../maps")
+	if err != nil {
+		t.Fatalf("load maps: %v", err)
+	}
+	store := &auditStore{Store: storage.NewMemoryStore()}
+	l := ledger.NewLedger(store)
+	um := user.NewManager(l, store)
+	for _, id := range []string{"alice", "root"} {
+		if err := um.CreateUser(&user.User{ID: id, Name: id}); err != nil {
+			t.Fatalf("create user %s: %v", id, err)
+		}
+	}
+	if err := um.AddCoins("alice", 1000); err != nil {
+		t.Fatalf("add coins: %v", err)
+	}
+	if err := um.SetRole("root", user.RoleAdmin); err != nil {
+		t.Fatalf("set role: %v", err)
+	}
+
+	h := NewAdminHandler(um, auth.NewCredentials(store), manager.NewGameManager(maps, store), l, audit.NewLog(store))
+	return h, store
+}
+
+// coinsOf 获取用户的余额
+func coinsOf(t *testing.T, h *AdminHandler, userID string) int {
+	t.Helper()
+
+	u, err := h.userManager.GetUser(userID)
+	if err != nil {
+		t.Fatalf("get user %s: %v", userID, err)
+	}
+	return u.Coins
+}
+
+func TestAdminActionRecordsAuditEntryWithActor(t *testing.T) {
+	h, _ := newAdminFixture(t)
+	vars := map[string]string{"userId": "alice"}
+
+	if code := serve(h.MintCoins, "root", `{"amount": 500, "reason": "compensation"}`, vars); code != http.StatusOK {
+		t.Fatalf("mint: got status %d", code)
+	}
+	if coins := coinsOf(t, h, "alice"); coins != 1500 {
+		t.Fatalf("got %d coins, want 1500", coins)
+	}
+
+	entries := h.audit.Entries(audit.Filter{})
+	if len(entries) != 1 {
+		t.Fatalf("got %d audit entries, want 1", len(entries))
+	}
+	if e := entries[0]; e.Actor != "root" || e.Action != audit.ActionMintCoins || e.Target != "alice" || e.Reason != "compensation" || e.Outcome != "" {
+		t.Fatalf("got audit entry %+v", e)
+	}
+
+	// 未说明原因时不执行操作
+	if code := serve(h.MintCoins, "root", `{"amount": 500}`, vars); code != http.StatusBadRequest {
+		t.Fatalf("mint without reason: got status %d, want %d", code, http.StatusBadRequest)
+	}
+	if coins := coinsOf(t, h, "alice"); coins != 1500 {
+		t.Fatalf("got %d coins after rejected mint, want 1500", coins)
+	}
+}
+
+func TestAdminActionNotAppliedWhenAuditFails(t *testing.T) {
+	h, store := newAdminFixture(t)
+	vars := map[string]string{"userId": "alice"}
+	store.down = true
+
+	tests := []struct {
+		name   string
+		handle http.HandlerFunc
+		body   string
+	}{
+		{"mint", h.MintCoins, `{"amount": 500, "reason": "test"}`},
+		{"burn", h.BurnCoins, `{"amount": 500, "reason": "test"}`},
+		{"role", h.SetRole, `{"role": "admin", "reason": "test"}`},
+		{"delete", h.DeleteUser, `{"reason": "test"}`},
+	}
+	for _, tt := range tests {
+		if code := serve(tt.handle, "root", tt.body, vars); code != http.StatusInternalServerError {
+			t.Fatalf("%s: got status %d, want %d", tt.name, code, http.StatusInternalServerError)
+		}
+	}
+
+	u, err := h.userManager.GetUser("alice")
+	if err != nil {
+		t.Fatalf("user deleted without an audit entry: %v", err)
+	}
+	if u.Coins != 1000 || u.Role != user.RolePlayer {
+		t.Fatalf("user changed without an audit entry: %+v", u)
+	}
+
+	// 审计日志恢复后重试只执行一次
+	store.down = false
+	if code := serve(h.MintCoins, "root", `{"amount": 500, "reason": "test"}`, vars); code != http.StatusOK {
+		t.Fatalf("retry mint: got status %d", code)
+	}
+	if coins := coinsOf(t, h, "alice"); coins != 1500 {
+		t.Fatalf("got %d coins after retry, want 1500", coins)
+	}
+}
+
+func TestFailedAdminActionRecordsFailure(t *testing.T) {
+	h, _ := newAdminFixture(t)
+
+	if code := serve(h.BurnCoins, "root", `{"amount": 5000, "reason": "chargeback"}`, map[string]string{"userId": "alice"}); code == http.StatusOK {
+		t.Fatal("burned more coins than the balance")
+	}
+	if coins := coinsOf(t, h, "alice"); coins != 1000 {
+		t.Fatalf("got %d coins, want 1000", coins)
+	}
+
+	entries := h.audit.Entries(audit.Filter{Action: audit.ActionBurnCoins})
+	if len(entries) != 2 {
+		t.Fatalf("got audit entries %+v, want the request and its failure", entries)
+	}
+	requested, failed := entries[0], entries[1]
+	if requested.Outcome != "" || failed.Outcome != audit.OutcomeFailed || failed.Actor != "root" || failed.Details["entry"] != requested.ID {
+		t.Fatalf("got audit entries %+v and %+v, want a failure referring to the request", requested, failed)
+	}
+}
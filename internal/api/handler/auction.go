@@ -0,0 +1,91 @@
+// internal/api/handler/auction.go
+package handler
+
+import (
+	"encoding/json"
+	"monopoly/internal/api/response"
+	"monopoly/pkg/utils"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// GetAuction 获取进行中或最近一次的拍卖
+func (h *GameHandler) GetAuction(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	auction, err := g.GetAuction()
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(auction))
+}
+
+// PlaceBid 拍卖出价
+func (h *GameHandler) PlaceBid(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	var req struct {
+		Amount int `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JsonError(w, utils.ErrInvalidInput)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	auction, err := g.PlaceBid(playerID, req.Amount)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(auction))
+}
+
+// DeclineProperty 放弃购买当前地产并发起拍卖
+func (h *GameHandler) DeclineProperty(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	auction, err := g.DeclineProperty(playerID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, response.Success(auction))
+}
@@ -0,0 +1,126 @@
+// internal/api/handler/auth_test.go
+package handler
+
+import (
+	"monopoly/internal/auth"
+	"monopoly/internal/game"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func TestGameActionsActAsTokenUser(t *testing.T) {
+	h, g := newHostFixture(t)
+	if err := g.StartGame(); err != nil {
+		t.Fatalf("start game: %v", err)
+	}
+	vars := map[string]string{"gameId": g.ID}
+	current := g.CurrentPlayerID
+	other := g.TurnOrder[1]
+	if other == current {
+		other = g.TurnOrder[0]
+	}
+
+	// 请求体中的 playerId 不能让其他玩家替当前玩家操作
+	body := `{"playerId": "` + current + `"}`
+	if code := serve(h.RollDice, other, body, vars); code != http.StatusUnauthorized {
+		t.Fatalf("roll for another player: got status %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := serve(h.EndTurn, other, body, vars); code != http.StatusUnauthorized {
+		t.Fatalf("end another player's turn: got status %d, want %d", code, http.StatusUnauthorized)
+	}
+	if g.CurrentPlayerID != current || countRolls(g) != 0 {
+		t.Fatalf("rejected requests changed the game: current player %s, %d rolls", g.CurrentPlayerID, countRolls(g))
+	}
+
+	// 当前玩家的请求以令牌用户的身份执行，忽略请求体中的 playerId
+	if code := serve(h.RollDice, current, `{"playerId": "`+other+`"}`, vars); code != http.StatusOK {
+		t.Fatalf("roll: got status %d", code)
+	}
+	var rolled *game.GameAction
+	for _, action := range g.Actions {
+		if action.Type == game.ActionRollDice {
+			rolled = action
+		}
+	}
+	if rolled == nil || rolled.PlayerID != current {
+		t.Fatalf("got roll action %+v, want one by %s", rolled, current)
+	}
+
+	// 路径中的 playerId 必须是令牌用户本人
+	leaveVars := map[string]string{"gameId": g.ID, "playerId": other}
+	if code := serve(h.LeaveGame, current, "", leaveVars); code != http.StatusForbidden {
+		t.Fatalf("leave as another player: got status %d, want %d", code, http.StatusForbidden)
+	}
+	if _, exists := g.Players[other]; !exists {
+		t.Fatalf("player %s removed by another player's request", other)
+	}
+}
+
+func TestGameRoutesRejectMissingOrBadToken(t *testing.T) {
+	h, g := newHostFixture(t)
+	if err := g.StartGame(); err != nil {
+		t.Fatalf("start game: %v", err)
+	}
+	issuer := auth.NewIssuer([]byte("test-secret"), time.Hour)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/games/{gameId}/roll", h.RollDice).Methods("POST")
+	router.HandleFunc("/api/games/{gameId}/end-turn", h.EndTurn).Methods("POST")
+	router.HandleFunc("/api/games/{gameId}/join", h.Join).Methods("POST")
+	router.Use(NewAuthHandler(issuer, nil, h.userManager).Authenticate)
+
+	valid, _, err := issuer.Issue(g.CurrentPlayerID)
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	forged, _, err := auth.NewIssuer([]byte("other-secret"), time.Hour).Issue(g.CurrentPlayerID)
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	tampered := valid[:len(valid)-2] + "xx"
+
+	tests := []struct {
+		name   string
+		header string
+		query  string
+	}{
+		{"missing", "", ""},
+		{"garbage", "Bearer garbage", ""},
+		{"not bearer", "Basic " + valid, ""},
+		{"wrong secret", "Bearer " + forged, ""},
+		{"tampered", "Bearer " + tampered, ""},
+		{"bad query token", "", "?token=garbage"},
+	}
+
+	for _, route := range []string{"roll", "end-turn", "join"} {
+		for _, tt := range tests {
+			r := httptest.NewRequest(http.MethodPost, "/api/games/"+g.ID+"/"+route+tt.query, strings.NewReader(`{"playerId": "`+g.CurrentPlayerID+`"}`))
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			if w.Code != http.StatusUnauthorized {
+				t.Fatalf("%s with %s token: got status %d, want %d", route, tt.name, w.Code, http.StatusUnauthorized)
+			}
+		}
+	}
+	if len(g.Players) != 3 || countRolls(g) != 0 {
+		t.Fatalf("rejected requests changed the game: %d players, %d rolls", len(g.Players), countRolls(g))
+	}
+}
+
+// countRolls 统计掷骰动作的数量
+func countRolls(g *game.Game) int {
+	count := 0
+	for _, action := range g.Actions {
+		if action.Type == game.ActionRollDice {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,149 @@
+// internal/api/handler/bank.go
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"monopoly/internal/api/response"
+	"monopoly/pkg/utils"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// TakeLoan 向银行贷款
+func (h *GameHandler) TakeLoan(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	var req struct {
+		Amount int `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JsonError(w, utils.ErrInvalidInput)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	action, err := g.TakeLoan(playerID, req.Amount)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(action))
+}
+
+// RepayLoan 偿还银行贷款，amount 为0或省略时还清全部本息
+func (h *GameHandler) RepayLoan(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	var req struct {
+		Amount int `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.JsonError(w, utils.ErrInvalidInput)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	action, err := g.RepayLoan(playerID, req.Amount)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(action))
+}
+
+// Deposit 存入银行
+func (h *GameHandler) Deposit(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	var req struct {
+		Amount int `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JsonError(w, utils.ErrInvalidInput)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	action, err := g.Deposit(playerID, req.Amount)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(action))
+}
+
+// Withdraw 取出银行存款，amount 为0或省略时全部取出
+func (h *GameHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	var req struct {
+		Amount int `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.JsonError(w, utils.ErrInvalidInput)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	action, err := g.Withdraw(playerID, req.Amount)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(action))
+}
@@ -0,0 +1,107 @@
+// internal/api/handler/events.go
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"monopoly/internal/api/response"
+	"monopoly/pkg/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// SSE 连接参数，测试中可缩短保活间隔
+var (
+	sseWriteWait         = 10 * time.Second // 单条消息的写超时
+	sseKeepaliveInterval = 15 * time.Second // 保活注释的发送间隔，防止代理断开空闲连接
+)
+
+// Events 以 Server-Sent Events 推送游戏动态，供无法使用 WebSocket 的客户端使用
+// 每条消息的 id 为截至该消息的最后一个动作序号，断线重连时按 Last-Event-ID 从该动作之后续传，
+// 续传可能重复推送该动作之后已收到的事件，客户端按 eventIndex 去重
+func (h *GameHandler) Events(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	lastAction, err := lastActionFromRequest(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	sub, err := h.gameManager.Broadcaster().Subscribe(gameID, lastAction)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // 关闭反向代理的响应缓冲
+	w.WriteHeader(http.StatusOK)
+
+	// 推送流不受服务器写超时限制，改为每次写入单独设置超时
+	rc := http.NewResponseController(w)
+	write := func(format string, args ...interface{}) error {
+		if err := rc.SetWriteDeadline(time.Now().Add(sseWriteWait)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	ticker := time.NewTicker(sseKeepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sub.Ready():
+			messages, open := sub.Next()
+			for _, message := range messages {
+				data, err := json.Marshal(message)
+				if err != nil {
+					return
+				}
+				if err := write("id: %d\nevent: %s\ndata: %s\n\n", message.ActionIndex, message.Type, data); err != nil {
+					return
+				}
+			}
+			if !open {
+				// 服务停止或积压过多，客户端按 Last-Event-ID 重连
+				return
+			}
+		case <-ticker.C:
+			if err := write(": keepalive\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// lastActionFromRequest 获取客户端断线前收到的最后一个动作序号
+// 依次读取 Last-Event-ID 请求头和 lastAction 查询参数，都没有时返回-1
+func lastActionFromRequest(r *http.Request) (int, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastAction")
+	}
+	if value == "" {
+		return -1, nil
+	}
+
+	lastAction, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, utils.ErrInvalidInput
+	}
+	return lastAction, nil
+}
@@ -0,0 +1,170 @@
+// internal/api/handler/events_test.go
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"monopoly/internal/game"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sseFrame 一条 SSE 消息或保活注释
+type sseFrame struct {
+	id      string
+	event   string
+	data    string
+	comment string
+}
+
+// sseStream 读取 SSE 响应
+type sseStream struct {
+	resp   *http.Response
+	reader *bufio.Reader
+}
+
+// openEvents 以 lastEventID 打开游戏的 SSE 推送，lastEventID 为空时不携带 Last-Event-ID
+func (f *streamFixture) openEvents(t *testing.T, lastEventID string) *sseStream {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, f.url(t, "http", "/api/games/"+f.game.ID+"/events", "", nil), nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open events: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return &sseStream{resp: resp, reader: bufio.NewReader(resp.Body)}
+}
+
+// next 读取下一条消息，超时则测试失败
+func (s *sseStream) next(t *testing.T) sseFrame {
+	t.Helper()
+
+	frames := make(chan sseFrame, 1)
+	errs := make(chan error, 1)
+	go func() {
+		var frame sseFrame
+		for {
+			line, err := s.reader.ReadString('\n')
+			if err != nil {
+				errs <- err
+				return
+			}
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "":
+				frames <- frame
+				return
+			case strings.HasPrefix(line, ":"):
+				frame.comment = strings.TrimSpace(line[1:])
+			default:
+				field, value, _ := strings.Cut(line, ": ")
+				switch field {
+				case "id":
+					frame.id = value
+				case "event":
+					frame.event = value
+				case "data":
+					frame.data = value
+				}
+			}
+		}
+	}()
+
+	select {
+	case frame := <-frames:
+		return frame
+	case err := <-errs:
+		t.Fatalf("read event: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+	}
+	return sseFrame{}
+}
+
+// message 解析消息数据
+func (f sseFrame) message(t *testing.T) wsMessage {
+	t.Helper()
+
+	var message wsMessage
+	if err := json.Unmarshal([]byte(f.data), &message); err != nil {
+		t.Fatalf("decode event data %q: %v", f.data, err)
+	}
+	return message
+}
+
+func TestEventsStreamSnapshotThenEvents(t *testing.T) {
+	f := newStreamFixture(t)
+	stream := f.openEvents(t, "")
+	if stream.resp.StatusCode != http.StatusOK || stream.resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("got status %d and content type %q", stream.resp.StatusCode, stream.resp.Header.Get("Content-Type"))
+	}
+
+	snapshot := stream.next(t)
+	if snapshot.event != "snapshot" || snapshot.id != strconv.Itoa(len(f.game.Actions)-1) {
+		t.Fatalf("got first frame %+v, want a snapshot with id %d", snapshot, len(f.game.Actions)-1)
+	}
+
+	// 新动作推送后 id 更新为该动作的序号
+	if _, err := f.game.RollDice(f.game.CurrentPlayerID); err != nil {
+		t.Fatalf("roll dice: %v", err)
+	}
+	for {
+		frame := stream.next(t)
+		if frame.event != "action" || !isAction(frame.message(t), game.ActionRollDice) {
+			continue
+		}
+		if want := strconv.Itoa(lastActionOf(f.game, game.ActionRollDice)); frame.id != want {
+			t.Fatalf("got roll action with id %s, want %s", frame.id, want)
+		}
+		return
+	}
+}
+
+func TestEventsResumeFromLastEventID(t *testing.T) {
+	f := newStreamFixture(t)
+	g := f.game
+	for i := 0; i < 3; i++ {
+		g.RollDice(g.CurrentPlayerID)
+		g.EndTurn(g.CurrentPlayerID)
+	}
+	lastAction := len(g.Actions) - 2
+
+	// 续传时不推送快照，从该动作之后的事件开始补发
+	stream := f.openEvents(t, strconv.Itoa(lastAction))
+	last := len(g.Events) - 1
+	for want := recordedAt(g, lastAction) + 1; want <= last; want++ {
+		frame := stream.next(t)
+		if message := frame.message(t); frame.event == "snapshot" || message.EventIndex != want {
+			t.Fatalf("got %s frame at event %d, want event %d", frame.event, message.EventIndex, want)
+		}
+	}
+
+	if stream := f.openEvents(t, "abc"); stream.resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("invalid Last-Event-ID: got status %d, want %d", stream.resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestEventsKeepalive(t *testing.T) {
+	interval := sseKeepaliveInterval
+	t.Cleanup(func() { sseKeepaliveInterval = interval })
+	sseKeepaliveInterval = 20 * time.Millisecond
+
+	f := newStreamFixture(t)
+	stream := f.openEvents(t, "")
+	if snapshot := stream.next(t); snapshot.event != "snapshot" {
+		t.Fatalf("got first frame %+v, want a snapshot", snapshot)
+	}
+	if frame := stream.next(t); frame.comment != "keepalive" {
+		t.Fatalf("got frame %+v, want a keepalive comment", frame)
+	}
+}
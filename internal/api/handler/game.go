@@ -3,6 +3,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"monopoly/internal/api/response"
 	"monopoly/internal/game"
 	"monopoly/internal/manager"
@@ -10,6 +11,7 @@ import (
 	"monopoly/pkg/utils"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -17,32 +19,78 @@ import (
 // GameHandler 游戏相关的HTTP请求处理器
 type GameHandler struct {
 	gameManager *manager.GameManager
+	settlement  *manager.Settlement
 	userManager *user.Manager
 }
 
 // NewGameHandler 创建新的游戏处理器
-func NewGameHandler(gm *manager.GameManager, um *user.Manager) *GameHandler {
+func NewGameHandler(gm *manager.GameManager, settlement *manager.Settlement, um *user.Manager) *GameHandler {
 	return &GameHandler{
 		gameManager: gm,
+		settlement:  settlement,
 		userManager: um,
 	}
 }
 
-// Create 创建新游戏
+// 长轮询参数
+const (
+	maxLongPollWait   = 60 * time.Second // 最长等待时间
+	longPollWriteWait = 10 * time.Second // 等待结束后写响应的超时
+)
+
+// Create 创建新游戏，令牌对应的用户成为游戏的主持人
 func (h *GameHandler) Create(w http.ResponseWriter, r *http.Request) {
+	hostID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
 	var req struct {
-		GameID string `json:"gameId"`
+		GameID   string          `json:"gameId"`
+		Seed     *int64          `json:"seed"`     // 可选，用于复现游戏
+		Preset   string          `json:"preset"`   // 可选，规则预设名称，默认 classic
+		Settings json.RawMessage `json:"settings"` // 可选，覆盖预设中的字段
+		Map      string          `json:"map"`      // 可选，地图名称
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		response.JsonError(w, utils.ErrInvalidInput)
 		return
 	}
 
-	newGame := h.gameManager.CreateGame(req.GameID)
-	response.JSON(w, http.StatusCreated, response.Success(newGame))
+	settings, err := game.PresetSettings(req.Preset)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+	if len(req.Settings) > 0 {
+		if err := json.Unmarshal(req.Settings, &settings); err != nil {
+			response.JsonError(w, utils.ErrInvalidInput)
+			return
+		}
+	}
+
+	if err := settings.Validate(); err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	seed := game.NewSeed()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	newGame, err := h.gameManager.CreateGame(req.GameID, hostID, seed, settings, req.Map)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	writeGame(w, http.StatusCreated, newGame)
 }
 
 // Get 获取游戏信息
+// 指定 sinceVersion 时等待游戏版本超过该值后返回，最长等待 wait（默认不等待），超时返回 304
 func (h *GameHandler) Get(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	gameID := vars["gameId"]
@@ -53,60 +101,178 @@ func (h *GameHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.JSON(w, http.StatusOK, response.Success(game))
+	query := r.URL.Query()
+	if value := query.Get("sinceVersion"); value != "" {
+		sinceVersion, err := strconv.Atoi(value)
+		if err != nil {
+			response.JsonError(w, utils.ErrInvalidInput)
+			return
+		}
+
+		wait := time.Duration(0)
+		if value := query.Get("wait"); value != "" {
+			wait, err = time.ParseDuration(value)
+			if err != nil || wait < 0 || wait > maxLongPollWait {
+				response.JsonError(w, utils.ErrInvalidInput)
+				return
+			}
+		}
+
+		if !waitForChange(w, r, game.Changed(sinceVersion), wait) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	writeGame(w, http.StatusOK, game)
 }
 
-// Join 加入游戏
+// Join 以令牌对应的用户加入游戏，从用户钱包托管买入金额作为初始金币
 func (h *GameHandler) Join(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	gameID := vars["gameId"]
 
-	var req struct {
-		UserID string `json:"userId"`
+	userID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+
+	g, err := h.settlement.Join(gameID, userID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	writeGame(w, http.StatusOK, g)
+}
+
+// StartGame 开始游戏，只有游戏的主持人或管理员可以开始
+func (h *GameHandler) StartGame(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	g, err := h.hostedGame(r, gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	if err := g.StartGame(); err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	writeGame(w, http.StatusOK, g)
+}
+
+// UpdateSettings 修改游戏的规则设置，只有主持人或管理员可以在游戏开始前修改
+// 请求体与创建游戏时的 settings 相同，只覆盖其中出现的字段
+func (h *GameHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	g, err := h.hostedGame(r, gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	settings := g.GetSettings()
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
 		response.JsonError(w, utils.ErrInvalidInput)
 		return
 	}
 
-	user, err := h.userManager.GetUser(req.UserID)
+	if err := h.settlement.UpdateSettings(gameID, settings); err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	writeGame(w, http.StatusOK, g)
+}
+
+// KickPlayer 将玩家移出等待开始的游戏并退还买入金额，只有主持人或管理员可以踢出
+// 主持人不能踢出自己；进行中的游戏只能由管理员通过管理接口踢出玩家
+func (h *GameHandler) KickPlayer(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+	playerID := vars["playerId"]
+
+	g, err := h.hostedGame(r, gameID)
 	if err != nil {
 		response.JsonError(w, err)
 		return
 	}
 
-	g, err := h.gameManager.GetGame(gameID)
+	if playerID == g.HostID {
+		response.JsonError(w, utils.ErrInvalidInput)
+		return
+	}
+
+	if err := g.RemovePlayer(playerID); err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(nil))
+}
+
+// PauseGame 暂停进行中的游戏，只有主持人或管理员可以暂停
+func (h *GameHandler) PauseGame(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	g, err := h.hostedGame(r, gameID)
 	if err != nil {
 		response.JsonError(w, err)
 		return
 	}
 
-	player := game.NewPlayer(user.ID, user.Name, user.Coins)
-	if err := g.AddPlayer(player); err != nil {
+	if err := g.Pause(game.PauseHost); err != nil {
 		response.JsonError(w, err)
 		return
 	}
 
-	response.JSON(w, http.StatusOK, response.Success(g))
+	writeGame(w, http.StatusOK, g)
 }
 
-// StartGame 开始游戏
-func (h *GameHandler) StartGame(w http.ResponseWriter, r *http.Request) {
+// ResumeGame 恢复暂停的游戏，只有主持人或管理员可以恢复
+func (h *GameHandler) ResumeGame(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	gameID := vars["gameId"]
 
-	g, err := h.gameManager.GetGame(gameID)
+	g, err := h.hostedGame(r, gameID)
 	if err != nil {
 		response.JsonError(w, err)
 		return
 	}
 
-	if err := g.StartGame(); err != nil {
+	if err := g.Resume(game.PauseHost); err != nil {
 		response.JsonError(w, err)
 		return
 	}
 
-	response.JSON(w, http.StatusOK, response.Success(g))
+	writeGame(w, http.StatusOK, g)
+}
+
+// hostedGame 获取由请求者主持的游戏，管理员可以管理任意游戏
+// 未登录返回 ErrUnauthorized，既不是主持人也不是管理员返回 ErrForbidden
+func (h *GameHandler) hostedGame(r *http.Request, gameID string) (*game.Game, error) {
+	userID, err := actingUser(r)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userID != g.HostID && !h.userManager.IsAdmin(userID) {
+		return nil, utils.ErrForbidden
+	}
+	return g, nil
 }
 
 // RollDice 掷骰子
@@ -114,11 +280,9 @@ func (h *GameHandler) RollDice(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	gameID := vars["gameId"]
 
-	var req struct {
-		PlayerID string `json:"playerId"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.JsonError(w, utils.ErrInvalidInput)
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
 		return
 	}
 
@@ -128,7 +292,7 @@ func (h *GameHandler) RollDice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	action, err := g.RollDice(req.PlayerID)
+	action, err := g.RollDice(playerID)
 	if err != nil {
 		response.JsonError(w, err)
 		return
@@ -144,11 +308,9 @@ func (h *GameHandler) BuyProperty(w http.ResponseWriter, r *http.Request) {
 	// position在这里不需要，因为使用玩家当前位置
 	// position, _ := strconv.Atoi(vars["position"])
 
-	var req struct {
-		PlayerID string `json:"playerId"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.JsonError(w, utils.ErrInvalidInput)
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
 		return
 	}
 
@@ -158,7 +320,7 @@ func (h *GameHandler) BuyProperty(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	action, err := g.BuyProperty(req.PlayerID)
+	action, err := g.BuyProperty(playerID)
 	if err != nil {
 		response.JsonError(w, err)
 		return
@@ -171,13 +333,108 @@ func (h *GameHandler) BuyProperty(w http.ResponseWriter, r *http.Request) {
 func (h *GameHandler) UpgradeProperty(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	gameID := vars["gameId"]
-	position, _ := strconv.Atoi(vars["position"])
+	position, err := strconv.Atoi(vars["position"])
+	if err != nil {
+		response.JsonError(w, utils.ErrInvalidPosition)
+		return
+	}
 
-	var req struct {
-		PlayerID string `json:"playerId"`
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.JsonError(w, utils.ErrInvalidInput)
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	action, err := g.UpgradeProperty(playerID, position)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(action))
+}
+
+// SellUpgrade 出售地产升级
+func (h *GameHandler) SellUpgrade(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+	position, err := strconv.Atoi(vars["position"])
+	if err != nil {
+		response.JsonError(w, utils.ErrInvalidPosition)
+		return
+	}
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	action, err := g.SellUpgrade(playerID, position)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(action))
+}
+
+// MortgageProperty 抵押地产
+func (h *GameHandler) MortgageProperty(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+	position, err := strconv.Atoi(vars["position"])
+	if err != nil {
+		response.JsonError(w, utils.ErrInvalidPosition)
+		return
+	}
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	action, err := g.MortgageProperty(playerID, position)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(action))
+}
+
+// UnmortgageProperty 赎回地产
+func (h *GameHandler) UnmortgageProperty(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+	position, err := strconv.Atoi(vars["position"])
+	if err != nil {
+		response.JsonError(w, utils.ErrInvalidPosition)
+		return
+	}
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
 		return
 	}
 
@@ -187,7 +444,7 @@ func (h *GameHandler) UpgradeProperty(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	action, err := g.UpgradeProperty(req.PlayerID, position)
+	action, err := g.UnmortgageProperty(playerID, position)
 	if err != nil {
 		response.JsonError(w, err)
 		return
@@ -196,16 +453,65 @@ func (h *GameHandler) UpgradeProperty(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, response.Success(action))
 }
 
+// PayDebt 偿还债务
+func (h *GameHandler) PayDebt(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	action, err := g.PayDebt(playerID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(action))
+}
+
+// DeclareBankruptcy 宣告破产
+func (h *GameHandler) DeclareBankruptcy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	if _, err := g.DeclareBankruptcy(playerID); err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	writeGame(w, http.StatusOK, g)
+}
+
 // EndTurn 结束回合
 func (h *GameHandler) EndTurn(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	gameID := vars["gameId"]
 
-	var req struct {
-		PlayerID string `json:"playerId"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.JsonError(w, utils.ErrInvalidInput)
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
 		return
 	}
 
@@ -215,12 +521,63 @@ func (h *GameHandler) EndTurn(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := g.NextTurn(); err != nil {
+	if err := g.EndTurn(playerID); err != nil {
 		response.JsonError(w, err)
 		return
 	}
 
-	response.JSON(w, http.StatusOK, response.Success(g))
+	writeGame(w, http.StatusOK, g)
+}
+
+// waitForChange 等待 changed 关闭，最长等待 wait，游戏发生变化时返回 true
+// 等待期间延长响应的写超时，使长轮询不受服务器写超时限制
+func waitForChange(w http.ResponseWriter, r *http.Request, changed <-chan struct{}, wait time.Duration) bool {
+	select {
+	case <-changed:
+		return true
+	default:
+	}
+	if wait == 0 {
+		return false
+	}
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Now().Add(wait + longPollWriteWait)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return false
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-changed:
+		return true
+	case <-timer.C:
+	case <-r.Context().Done():
+	}
+	return false
+}
+
+// writeGame 返回游戏信息
+func writeGame(w http.ResponseWriter, statusCode int, g *game.Game) {
+	data, err := snapshotGame(g)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, statusCode, response.Success(data))
+}
+
+// snapshotGame 序列化游戏信息
+// 游戏可能同时被调度器、WebSocket 连接和其他请求修改，需在持有读锁时完成序列化
+func snapshotGame(g *game.Game) (json.RawMessage, error) {
+	var data []byte
+	var err error
+	g.View(func(g *game.Game) {
+		data, err = json.Marshal(g)
+	})
+	return data, err
 }
 
 // GetGameStatus 获取游戏状态
@@ -234,25 +591,32 @@ func (h *GameHandler) GetGameStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 构建游戏状态
-	status := struct {
+	// 构建游戏状态，需持有游戏读锁
+	type gameStatus struct {
 		GameID        string          `json:"gameId"`
 		Status        game.GameStatus `json:"status"`
 		PlayerCount   int             `json:"playerCount"`
 		CurrentPlayer string          `json:"currentPlayer"`
+		TurnOrder     []string        `json:"turnOrder"`
 		RemainingTime int             `json:"remainingTime"`
 		TurnTimeLeft  int             `json:"turnTimeLeft"`
 		PrizePool     int             `json:"prizePool"`
-	}{
-		GameID:        g.ID,
-		Status:        g.Status,
-		PlayerCount:   len(g.Players),
-		CurrentPlayer: g.CurrentPlayerID,
-		RemainingTime: g.GetRemainingTime(),
-		TurnTimeLeft:  g.GetTurnTimeLeft(),
-		PrizePool:     g.PrizePool,
 	}
 
+	var status gameStatus
+	g.View(func(g *game.Game) {
+		status = gameStatus{
+			GameID:        g.ID,
+			Status:        g.Status,
+			PlayerCount:   len(g.Players),
+			CurrentPlayer: g.CurrentPlayerID,
+			TurnOrder:     g.TurnOrder,
+			RemainingTime: g.GetRemainingTime(),
+			TurnTimeLeft:  g.GetTurnTimeLeft(),
+			PrizePool:     g.PrizePool,
+		}
+	})
+
 	response.JSON(w, http.StatusOK, response.Success(status))
 }
 
@@ -268,34 +632,78 @@ func (h *GameHandler) GetPlayerStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	player, exists := g.Players[playerID]
+	var status game.PlayerStatusView
+	exists := false
+	g.View(func(g *game.Game) {
+		var player *game.Player
+		if player, exists = g.Players[playerID]; exists {
+			status = player.GetStatus()
+		}
+	})
 	if !exists {
 		response.JsonError(w, utils.ErrPlayerNotFound)
 		return
 	}
 
-	status := player.GetStatus()
 	response.JSON(w, http.StatusOK, response.Success(status))
 }
 
-// LeaveGame 离开游戏
+// LeaveGame 离开游戏，托管的买入金额退还用户钱包，玩家只能让自己离开
 func (h *GameHandler) LeaveGame(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	gameID := vars["gameId"]
 	playerID := vars["playerId"]
 
+	if err := authorizeSelf(r, playerID); err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
 	g, err := h.gameManager.GetGame(gameID)
 	if err != nil {
 		response.JsonError(w, err)
 		return
 	}
 
-	// 只有在等待状态才能离开游戏
-	if g.Status != game.StatusWaiting {
-		response.JsonError(w, utils.ErrGameInProgress)
+	if err := g.RemovePlayer(playerID); err != nil {
+		response.JsonError(w, err)
 		return
 	}
 
-	delete(g.Players, playerID)
 	response.JSON(w, http.StatusOK, response.Success(nil))
 }
+
+// Replay 重放事件日志，重建游戏在指定事件处的状态
+func (h *GameHandler) Replay(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	events := g.GetEvents()
+	upTo := len(events) - 1
+	if value := r.URL.Query().Get("upTo"); value != "" {
+		upTo, err = strconv.Atoi(value)
+		if err != nil {
+			response.JsonError(w, utils.ErrInvalidInput)
+			return
+		}
+	}
+
+	replayed, err := game.Replay(events, upTo)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(map[string]interface{}{
+		"upTo":        upTo,
+		"totalEvents": len(events),
+		"game":        replayed,
+		"events":      replayed.Events,
+	}))
+}
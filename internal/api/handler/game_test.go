@@ -0,0 +1,258 @@
+// internal/api/handler/game_test.go
+package handler
+
+import (
+	"encoding/json"
+	"monopoly/internal/auth"
+	"monopoly/internal/game"
+	"monopoly/internal/ledger"
+	"monopoly/internal/manager"
+	"monopoly/internal/storage"
+	"monopoly/internal/user"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// newHostFixture 创建由 host 主持的等待中游戏，host、guest 和 other 已加入，admin 是管理员
+func newHostFixture(t *testing.T) (*GameHandler, *game.Game) {
+	t.Helper()
+
+	maps, err := game.LoadMaps("../../../maps")
+	if err != nil {
+		t.Fatalf("load maps: %v", err)
+	}
+	store := storage.NewMemoryStore()
+	um := user.NewManager(ledger.NewLedger(store), store)
+	gm := manager.NewGameManager(maps, store)
+	settlement := manager.NewSettlement(gm, um)
+
+	for _, id := range []string{"host", "guest", "other", "admin"} {
+		if err := um.CreateUser(&user.User{ID: id, Name: id, Coins: 10000}); err != nil {
+			t.Fatalf("create user %s: %v", id, err)
+		}
+	}
+	if err := um.SetRole("admin", user.RoleAdmin); err != nil {
+		t.Fatalf("set role: %v", err)
+	}
+
+	g, err := gm.CreateGame("g1", "host", 1, game.DefaultSettings(), "small")
+	if err != nil {
+		t.Fatalf("create game: %v", err)
+	}
+	for _, id := range []string{"host", "guest", "other"} {
+		if _, err := settlement.Join(g.ID, id); err != nil {
+			t.Fatalf("join %s: %v", id, err)
+		}
+	}
+	return NewGameHandler(gm, settlement, um), g
+}
+
+// serve 以 userID 的身份调用处理函数，userID 为空时不携带身份，返回响应状态码
+func serve(handle http.HandlerFunc, userID, body string, vars map[string]string) int {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if userID != "" {
+		r = r.WithContext(auth.NewContext(r.Context(), &auth.Claims{UserID: userID}))
+	}
+	r = mux.SetURLVars(r, vars)
+
+	w := httptest.NewRecorder()
+	handle(w, r)
+	return w.Code
+}
+
+func TestHostRoutesRejectNonHost(t *testing.T) {
+	h, g := newHostFixture(t)
+	gameVars := map[string]string{"gameId": g.ID}
+	kickVars := map[string]string{"gameId": g.ID, "playerId": "other"}
+
+	tests := []struct {
+		name   string
+		handle http.HandlerFunc
+		body   string
+		vars   map[string]string
+	}{
+		{"settings", h.UpdateSettings, `{"maxPlayers": 3}`, gameVars},
+		{"kick", h.KickPlayer, "", kickVars},
+		{"pause", h.PauseGame, "", gameVars},
+		{"resume", h.ResumeGame, "", gameVars},
+		{"start", h.StartGame, "", gameVars},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := serve(tt.handle, "guest", tt.body, tt.vars); code != http.StatusForbidden {
+				t.Fatalf("non-host: got status %d, want %d", code, http.StatusForbidden)
+			}
+			if code := serve(tt.handle, "", tt.body, tt.vars); code != http.StatusUnauthorized {
+				t.Fatalf("anonymous: got status %d, want %d", code, http.StatusUnauthorized)
+			}
+		})
+	}
+
+	if g.Status != game.StatusWaiting || len(g.Players) != 3 || g.Settings.MaxPlayers != game.DefaultSettings().MaxPlayers {
+		t.Fatalf("game changed by rejected requests: status %s, %d players, maxPlayers %d",
+			g.Status, len(g.Players), g.Settings.MaxPlayers)
+	}
+}
+
+func TestHostManagesOwnGame(t *testing.T) {
+	h, g := newHostFixture(t)
+	gameVars := map[string]string{"gameId": g.ID}
+
+	if code := serve(h.UpdateSettings, "host", `{"maxPlayers": 3, "turnTimeout": "45s"}`, gameVars); code != http.StatusOK {
+		t.Fatalf("update settings: got status %d", code)
+	}
+	if g.Settings.MaxPlayers != 3 || time.Duration(g.Settings.TurnTimeout) != 45*time.Second || g.Settings.BuyIn != game.DefaultSettings().BuyIn {
+		t.Fatalf("got settings %+v, want maxPlayers 3 and turnTimeout 45s with other fields kept", g.Settings)
+	}
+
+	// 已有玩家托管了买入金额，不能再修改买入金额
+	if code := serve(h.UpdateSettings, "host", `{"buyIn": 8000}`, gameVars); code != http.StatusBadRequest {
+		t.Fatalf("change buy-in: got status %d, want %d", code, http.StatusBadRequest)
+	}
+
+	// 主持人不能踢出自己，管理员可以管理任意游戏
+	if code := serve(h.KickPlayer, "host", "", map[string]string{"gameId": g.ID, "playerId": "host"}); code != http.StatusBadRequest {
+		t.Fatalf("kick self: got status %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := serve(h.KickPlayer, "admin", "", map[string]string{"gameId": g.ID, "playerId": "other"}); code != http.StatusOK {
+		t.Fatalf("admin kick: got status %d", code)
+	}
+	if _, exists := g.Players["other"]; exists {
+		t.Fatal("kicked player is still in the game")
+	}
+	if u, err := h.userManager.GetUser("other"); err != nil || u.Coins != 10000 {
+		t.Fatalf("kicked player's buy-in not refunded: %+v, %v", u, err)
+	}
+
+	if code := serve(h.StartGame, "host", "", gameVars); code != http.StatusOK {
+		t.Fatalf("start: got status %d", code)
+	}
+
+	// 开始后规则不再改变
+	if code := serve(h.UpdateSettings, "host", `{"maxPlayers": 4}`, gameVars); code != http.StatusConflict {
+		t.Fatalf("update settings after start: got status %d, want %d", code, http.StatusConflict)
+	}
+	if code := serve(h.PauseGame, "host", "", gameVars); code != http.StatusOK || g.Status != game.StatusPaused {
+		t.Fatalf("pause: got status %d and game status %s", code, g.Status)
+	}
+	if code := serve(h.ResumeGame, "host", "", gameVars); code != http.StatusOK || g.Status != game.StatusPlaying {
+		t.Fatalf("resume: got status %d and game status %s", code, g.Status)
+	}
+}
+
+func TestReplayValidatesUpTo(t *testing.T) {
+	h, g := newHostFixture(t)
+	events := len(g.GetEvents())
+
+	tests := []struct {
+		query     string
+		gameID    string
+		want      int
+		wantUpTo  int
+		wantCount int
+	}{
+		{"", g.ID, http.StatusOK, events - 1, events},
+		{"?upTo=0", g.ID, http.StatusOK, 0, 1},
+		{"?upTo=1", g.ID, http.StatusOK, 1, 2},
+		{"?upTo=abc", g.ID, http.StatusBadRequest, 0, 0},
+		{"?upTo=-1", g.ID, http.StatusBadRequest, 0, 0},
+		{"?upTo=" + strconv.Itoa(events), g.ID, http.StatusBadRequest, 0, 0},
+		{"", "missing", http.StatusNotFound, 0, 0},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+		r = mux.SetURLVars(r, map[string]string{"gameId": tt.gameID})
+		w := httptest.NewRecorder()
+		h.Replay(w, r)
+
+		if w.Code != tt.want {
+			t.Fatalf("%s%s: got status %d, want %d", tt.gameID, tt.query, w.Code, tt.want)
+		}
+		if tt.want != http.StatusOK {
+			continue
+		}
+		var body struct {
+			Data struct {
+				UpTo   int               `json:"upTo"`
+				Events []json.RawMessage `json:"events"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if body.Data.UpTo != tt.wantUpTo || len(body.Data.Events) != tt.wantCount {
+			t.Fatalf("%s: got upTo %d with %d events, want %d with %d",
+				tt.query, body.Data.UpTo, len(body.Data.Events), tt.wantUpTo, tt.wantCount)
+		}
+	}
+}
+
+// getGame 以长轮询参数请求游戏信息，返回响应
+func getGame(h *GameHandler, gameID, query string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/"+query, nil)
+	r = mux.SetURLVars(r, map[string]string{"gameId": gameID})
+	w := httptest.NewRecorder()
+	h.Get(w, r)
+	return w
+}
+
+func TestGetLongPoll(t *testing.T) {
+	h, g := newHostFixture(t)
+	version := g.Version
+	since := "?sinceVersion=" + strconv.Itoa(version)
+
+	// 版本已超过 sinceVersion 时立即返回
+	if w := getGame(h, g.ID, "?sinceVersion="+strconv.Itoa(version-1)+"&wait=30s"); w.Code != http.StatusOK {
+		t.Fatalf("older version: got status %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// 等待超时没有变化时返回304
+	started := time.Now()
+	if w := getGame(h, g.ID, since+"&wait=50ms"); w.Code != http.StatusNotModified {
+		t.Fatalf("no change: got status %d, want %d", w.Code, http.StatusNotModified)
+	}
+	if elapsed := time.Since(started); elapsed < 50*time.Millisecond {
+		t.Fatalf("returned after %v, want to wait 50ms", elapsed)
+	}
+	if w := getGame(h, g.ID, since); w.Code != http.StatusNotModified {
+		t.Fatalf("no change without wait: got status %d, want %d", w.Code, http.StatusNotModified)
+	}
+
+	// 等待期间游戏变化时立即返回新版本
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		settings := g.GetSettings()
+		settings.MaxPlayers = 3
+		g.UpdateSettings(settings)
+	}()
+	started = time.Now()
+	w := getGame(h, g.ID, since+"&wait=30s")
+	if w.Code != http.StatusOK || time.Since(started) > 10*time.Second {
+		t.Fatalf("change: got status %d after %v, want %d as soon as the game changes", w.Code, time.Since(started), http.StatusOK)
+	}
+	var body struct {
+		Data struct {
+			Version int `json:"version"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Data.Version <= version {
+		t.Fatalf("got version %d, want greater than %d", body.Data.Version, version)
+	}
+
+	for _, query := range []string{"?sinceVersion=abc", since + "&wait=soon", since + "&wait=-1s", since + "&wait=2m"} {
+		if w := getGame(h, g.ID, query); w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: got status %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
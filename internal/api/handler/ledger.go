@@ -0,0 +1,50 @@
+// internal/api/handler/ledger.go
+package handler
+
+import (
+	"monopoly/internal/api/response"
+	"monopoly/internal/ledger"
+	"monopoly/pkg/utils"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// LedgerHandler 账本相关的HTTP请求处理器
+type LedgerHandler struct {
+	ledger *ledger.Ledger
+}
+
+// NewLedgerHandler 创建新的账本处理器
+func NewLedgerHandler(l *ledger.Ledger) *LedgerHandler {
+	return &LedgerHandler{
+		ledger: l,
+	}
+}
+
+// GetEntries 查询涉及指定账户的账本分录，只能查询自己的钱包账户，管理员查询任意账户使用管理接口
+func (h *LedgerHandler) GetEntries(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	account := ledger.Account(vars["account"])
+
+	userID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+	if account != ledger.UserAccount(userID) {
+		response.JsonError(w, utils.ErrForbidden)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(ledgerEntries(h.ledger, account)))
+}
+
+// ledgerEntries 生成账户余额和分录的响应数据
+func ledgerEntries(l *ledger.Ledger, account ledger.Account) map[string]interface{} {
+	return map[string]interface{}{
+		"account": account,
+		"balance": l.Balance(account),
+		"entries": l.Entries(account),
+	}
+}
@@ -0,0 +1,25 @@
+// internal/api/handler/map.go
+package handler
+
+import (
+	"monopoly/internal/api/response"
+	"monopoly/internal/game"
+	"net/http"
+)
+
+// MapHandler 地图相关的HTTP请求处理器
+type MapHandler struct {
+	maps *game.MapRegistry
+}
+
+// NewMapHandler 创建新的地图处理器
+func NewMapHandler(maps *game.MapRegistry) *MapHandler {
+	return &MapHandler{
+		maps: maps,
+	}
+}
+
+// List 获取所有可用地图
+func (h *MapHandler) List(w http.ResponseWriter, r *http.Request) {
+	response.JSON(w, http.StatusOK, response.Success(h.maps.List()))
+}
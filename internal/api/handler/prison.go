@@ -0,0 +1,61 @@
+// internal/api/handler/prison.go
+package handler
+
+import (
+	"monopoly/internal/api/response"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// PayBail 支付保释金出狱
+func (h *GameHandler) PayBail(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	action, err := g.PayBail(playerID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(action))
+}
+
+// UsePrisonCard 使用出狱许可证
+func (h *GameHandler) UsePrisonCard(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	action, err := g.UsePrisonCard(playerID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(action))
+}
@@ -0,0 +1,138 @@
+// internal/api/handler/trade.go
+package handler
+
+import (
+	"encoding/json"
+	"monopoly/internal/api/response"
+	"monopoly/internal/game"
+	"monopoly/pkg/utils"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// ListTrades 获取游戏中的交易报价
+func (h *GameHandler) ListTrades(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(g.GetTrades()))
+}
+
+// ProposeTrade 发起交易
+func (h *GameHandler) ProposeTrade(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	var req struct {
+		ToPlayerID string `json:"toPlayerId"`
+		game.TradeTerms
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JsonError(w, utils.ErrInvalidInput)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	offer, err := g.ProposeTrade(playerID, req.ToPlayerID, req.TradeTerms)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, response.Success(offer))
+}
+
+// AcceptTrade 接受交易
+func (h *GameHandler) AcceptTrade(w http.ResponseWriter, r *http.Request) {
+	h.respondTrade(w, r, func(g *game.Game, playerID, tradeID string) (*game.TradeOffer, error) {
+		return g.AcceptTrade(playerID, tradeID)
+	})
+}
+
+// RejectTrade 拒绝交易
+func (h *GameHandler) RejectTrade(w http.ResponseWriter, r *http.Request) {
+	h.respondTrade(w, r, func(g *game.Game, playerID, tradeID string) (*game.TradeOffer, error) {
+		return g.RejectTrade(playerID, tradeID)
+	})
+}
+
+// CounterTrade 对交易还价
+func (h *GameHandler) CounterTrade(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+	tradeID := vars["tradeId"]
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	var req struct {
+		game.TradeTerms
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JsonError(w, utils.ErrInvalidInput)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	offer, err := g.CounterTrade(playerID, tradeID, req.TradeTerms)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, response.Success(offer))
+}
+
+// respondTrade 处理只需玩家ID的交易操作
+func (h *GameHandler) respondTrade(w http.ResponseWriter, r *http.Request,
+	op func(g *game.Game, playerID, tradeID string) (*game.TradeOffer, error)) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+	tradeID := vars["tradeId"]
+
+	playerID, err := actingUser(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	offer, err := op(g, playerID, tradeID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.Success(offer))
+}
@@ -4,6 +4,7 @@ package handler
 import (
 	"encoding/json"
 	"monopoly/internal/api/response"
+	"monopoly/internal/auth"
 	"monopoly/internal/user"
 	"monopoly/pkg/utils"
 	"net/http"
@@ -14,21 +15,23 @@ import (
 // UserHandler 用户相关的HTTP请求处理器
 type UserHandler struct {
 	userManager *user.Manager
+	credentials *auth.Credentials
 }
 
 // NewUserHandler 创建新的用户处理器
-func NewUserHandler(um *user.Manager) *UserHandler {
+func NewUserHandler(um *user.Manager, credentials *auth.Credentials) *UserHandler {
 	return &UserHandler{
 		userManager: um,
+		credentials: credentials,
 	}
 }
 
-// Create 创建新用户
+// Create 创建新用户并设置登录密码，新用户为普通玩家，余额为0，金币由管理员发放
 func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		ID    string `json:"id"`
-		Name  string `json:"name"`
-		Coins int    `json:"coins"`
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		Password string `json:"password"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -42,17 +45,15 @@ func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 验证初始金币数量
-	if req.Coins < 0 {
-		response.JsonError(w, utils.ErrInvalidInput)
+	if err := auth.ValidatePassword(req.Password); err != nil {
+		response.JsonError(w, err)
 		return
 	}
 
 	// 创建新用户
 	newUser := &user.User{
-		ID:    req.ID,
-		Name:  req.Name,
-		Coins: req.Coins,
+		ID:   req.ID,
+		Name: req.Name,
 	}
 
 	if err := h.userManager.CreateUser(newUser); err != nil {
@@ -60,6 +61,11 @@ func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.credentials.SetPassword(newUser.ID, req.Password); err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
 	response.JSON(w, http.StatusCreated, response.Success(newUser))
 }
 
@@ -77,11 +83,16 @@ func (h *UserHandler) Get(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, response.Success(user))
 }
 
-// Update 更新用户信息
+// Update 更新用户信息，只能修改自己的信息
 func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID := vars["userId"]
 
+	if err := authorizeSelf(r, userID); err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
 	var req struct {
 		Name string `json:"name"`
 	}
@@ -111,84 +122,22 @@ func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, response.Success(user))
 }
 
-// AddCoins 为用户添加游戏币
-func (h *UserHandler) AddCoins(w http.ResponseWriter, r *http.Request) {
+// Delete 注销用户，只能注销自己，管理员删除其他用户使用管理接口
+func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID := vars["userId"]
 
-	var req struct {
-		Amount int `json:"amount"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.JsonError(w, utils.ErrInvalidInput)
-		return
-	}
-
-	// 验证金币数量
-	if req.Amount <= 0 {
-		response.JsonError(w, utils.ErrInvalidInput)
-		return
-	}
-
-	// 验证用户是否存在
-	if _, err := h.userManager.GetUser(userID); err != nil {
+	if err := authorizeSelf(r, userID); err != nil {
 		response.JsonError(w, err)
 		return
 	}
 
-	// 添加金币
-	if err := h.userManager.AddCoins(userID, req.Amount); err != nil {
-		response.JsonError(w, err)
-		return
-	}
-
-	// 获取更新后的用户信息
-	updatedUser, err := h.userManager.GetUser(userID)
-	if err != nil {
-		response.JsonError(w, err)
-		return
-	}
-
-	response.JSON(w, http.StatusOK, response.Success(updatedUser))
-}
-
-// DeductCoins 扣除用户游戏币
-func (h *UserHandler) DeductCoins(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID := vars["userId"]
-
-	var req struct {
-		Amount int `json:"amount"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.JsonError(w, utils.ErrInvalidInput)
-		return
-	}
-
-	// 验证金币数量
-	if req.Amount <= 0 {
-		response.JsonError(w, utils.ErrInvalidInput)
-		return
-	}
-
-	if err := h.userManager.DeductCoins(userID, req.Amount); err != nil {
+	if err := h.userManager.DeleteUser(userID); err != nil {
 		response.JsonError(w, err)
 		return
 	}
 
-	// 重新获取更新后的用户信息
-	updatedUser, _ := h.userManager.GetUser(userID)
-	response.JSON(w, http.StatusOK, response.Success(updatedUser))
-}
-
-// Delete 删除用户
-func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID := vars["userId"]
-
-	if err := h.userManager.DeleteUser(userID); err != nil {
+	if err := h.credentials.Delete(userID); err != nil {
 		response.JsonError(w, err)
 		return
 	}
@@ -0,0 +1,304 @@
+// internal/api/handler/ws.go
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"monopoly/internal/api/response"
+	"monopoly/internal/game"
+	"monopoly/internal/manager"
+	"monopoly/pkg/utils"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+// WebSocket 连接参数，测试中可缩短心跳间隔
+var (
+	wsWriteWait      = 10 * time.Second    // 单条消息的写超时
+	wsPongWait       = 60 * time.Second    // 超过该时间未收到客户端消息或 pong 即断开
+	wsPingPeriod     = wsPongWait * 9 / 10 // 心跳 ping 的发送间隔
+	wsMaxMessageSize = int64(64 * 1024)    // 客户端消息的大小上限
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+}
+
+// WebSocketHandler 通过 WebSocket 实时推送游戏动态并接收回合操作
+type WebSocketHandler struct {
+	gameManager *manager.GameManager
+}
+
+// NewWebSocketHandler 创建新的 WebSocket 处理器
+func NewWebSocketHandler(gm *manager.GameManager) *WebSocketHandler {
+	return &WebSocketHandler{
+		gameManager: gm,
+	}
+}
+
+// wsRequest 客户端通过 WebSocket 发送的回合操作
+type wsRequest struct {
+	ID         string `json:"id"` // 可选，原样带回操作结果
+	Action     string `json:"action"`
+	Position   int    `json:"position"`
+	Amount     int    `json:"amount"`
+	TradeID    string `json:"tradeId"`
+	ToPlayerID string `json:"toPlayerId"`
+	game.TradeTerms
+}
+
+// wsReply 回合操作的结果
+type wsReply struct {
+	Type      string `json:"type"` // 固定为 result
+	RequestID string `json:"requestId,omitempty"`
+	response.Response
+}
+
+// wsAction 执行一种回合操作
+type wsAction func(g *game.Game, playerID string, req *wsRequest) (interface{}, error)
+
+// wsActions 可通过 WebSocket 执行的回合操作，与对应的 HTTP 接口行为一致
+var wsActions = map[string]wsAction{
+	"roll": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.RollDice(playerID)
+	},
+	"buy": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.BuyProperty(playerID)
+	},
+	"decline": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.DeclineProperty(playerID)
+	},
+	"bid": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.PlaceBid(playerID, req.Amount)
+	},
+	"upgrade": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.UpgradeProperty(playerID, req.Position)
+	},
+	"sellUpgrade": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.SellUpgrade(playerID, req.Position)
+	},
+	"mortgage": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.MortgageProperty(playerID, req.Position)
+	},
+	"unmortgage": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.UnmortgageProperty(playerID, req.Position)
+	},
+	"payDebt": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.PayDebt(playerID)
+	},
+	"bankruptcy": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.DeclareBankruptcy(playerID)
+	},
+	"bail": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.PayBail(playerID)
+	},
+	"prisonCard": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.UsePrisonCard(playerID)
+	},
+	"loan": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.TakeLoan(playerID, req.Amount)
+	},
+	"repay": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.RepayLoan(playerID, req.Amount)
+	},
+	"deposit": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.Deposit(playerID, req.Amount)
+	},
+	"withdraw": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.Withdraw(playerID, req.Amount)
+	},
+	"proposeTrade": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.ProposeTrade(playerID, req.ToPlayerID, req.TradeTerms)
+	},
+	"acceptTrade": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.AcceptTrade(playerID, req.TradeID)
+	},
+	"rejectTrade": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.RejectTrade(playerID, req.TradeID)
+	},
+	"counterTrade": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return g.CounterTrade(playerID, req.TradeID, req.TradeTerms)
+	},
+	"endTurn": func(g *game.Game, playerID string, req *wsRequest) (interface{}, error) {
+		return nil, g.EndTurn(playerID)
+	},
+}
+
+// Connect 建立 WebSocket 连接，推送游戏快照和之后的全部动态
+// 令牌对应的用户是游戏中的玩家时以玩家身份连接并可发送回合操作，未携带令牌或不是玩家时为观战者；
+// 查询参数 lastAction 为断线前收到的最后一个动作序号，重连时从该动作之后续传
+func (h *WebSocketHandler) Connect(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	gameID := vars["gameId"]
+
+	lastAction, err := lastActionFromRequest(r)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	g, err := h.gameManager.GetGame(gameID)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	playerID := ""
+	if userID, err := actingUser(r); err == nil {
+		g.View(func(g *game.Game) {
+			if _, exists := g.Players[userID]; exists {
+				playerID = userID
+			}
+		})
+	}
+
+	sub, err := h.gameManager.Broadcaster().Subscribe(gameID, lastAction)
+	if err != nil {
+		response.JsonError(w, err)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade 已向客户端返回错误
+		sub.Close()
+		return
+	}
+
+	client := &wsClient{
+		conn:     conn,
+		sub:      sub,
+		games:    h.gameManager,
+		gameID:   gameID,
+		playerID: playerID,
+		done:     make(chan struct{}),
+	}
+	go client.writeLoop()
+	client.readLoop()
+}
+
+// wsClient 一个 WebSocket 连接，读写各占一个 goroutine
+type wsClient struct {
+	conn     *websocket.Conn
+	sub      *manager.Subscription
+	games    *manager.GameManager
+	gameID   string
+	playerID string
+	done     chan struct{} // 读循环退出时关闭
+	mutex    sync.Mutex    // 串行化消息写入
+}
+
+// readLoop 读取客户端发送的回合操作并回复结果，连接断开或心跳超时时退出
+func (c *wsClient) readLoop() {
+	defer func() {
+		close(c.done)
+		c.sub.Close()
+	}()
+
+	c.conn.SetReadLimit(wsMaxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Printf("websocket %s: read: %v", c.gameID, err)
+			}
+			return
+		}
+		c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+		if err := c.write(c.handle(data)); err != nil {
+			return
+		}
+	}
+}
+
+// writeLoop 推送订阅的消息并定时发送心跳，订阅关闭或连接断开时关闭连接
+func (c *wsClient) writeLoop() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case <-c.sub.Ready():
+			messages, open := c.sub.Next()
+			for _, message := range messages {
+				if err := c.write(message); err != nil {
+					return
+				}
+			}
+			if !open {
+				// 服务停止或积压过多，客户端应携带 lastAction 重连
+				closeMessage := websocket.FormatCloseMessage(websocket.CloseGoingAway, "subscription closed")
+				c.conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(wsWriteWait))
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// write 发送一条 JSON 消息
+func (c *wsClient) write(v interface{}) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return c.conn.WriteJSON(v)
+}
+
+// handle 执行客户端发送的回合操作，观战者不能操作
+// 操作产生的事件另行推送，可能先于结果到达
+func (c *wsClient) handle(data []byte) wsReply {
+	var req wsRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return newWsReply(req.ID, nil, utils.ErrInvalidInput)
+	}
+
+	if c.playerID == "" {
+		return newWsReply(req.ID, nil, utils.ErrUnauthorized)
+	}
+
+	action, ok := wsActions[req.Action]
+	if !ok {
+		return newWsReply(req.ID, nil, utils.ErrInvalidAction)
+	}
+
+	g, err := c.games.GetGame(c.gameID)
+	if err != nil {
+		return newWsReply(req.ID, nil, err)
+	}
+
+	result, err := action(g, c.playerID, &req)
+	return newWsReply(req.ID, result, err)
+}
+
+// newWsReply 创建回合操作的结果消息
+func newWsReply(requestID string, data interface{}, err error) wsReply {
+	reply := wsReply{
+		Type:      "result",
+		RequestID: requestID,
+		Response:  response.Success(data),
+	}
+	if err != nil {
+		reply.Response = response.Error(err)
+	}
+	return reply
+}
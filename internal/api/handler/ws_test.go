@@ -0,0 +1,256 @@
+// internal/api/handler/ws_test.go
+package handler
+
+import (
+	"encoding/json"
+	"monopoly/internal/auth"
+	"monopoly/internal/game"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+// streamFixture 推送接口的测试服务器，游戏已开始
+type streamFixture struct {
+	server *httptest.Server
+	issuer *auth.Issuer
+	game   *game.Game
+}
+
+// newStreamFixture 使用 newHostFixture 的游戏启动测试服务器，请求经过认证中间件
+func newStreamFixture(t *testing.T) *streamFixture {
+	t.Helper()
+
+	h, g := newHostFixture(t)
+	if err := g.StartGame(); err != nil {
+		t.Fatalf("start game: %v", err)
+	}
+	issuer := auth.NewIssuer([]byte("test-secret"), time.Hour)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/games/{gameId}/ws", NewWebSocketHandler(h.gameManager).Connect)
+	router.HandleFunc("/api/games/{gameId}/events", h.Events)
+	router.Use(NewAuthHandler(issuer, nil, h.userManager).Authenticate)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return &streamFixture{server: server, issuer: issuer, game: g}
+}
+
+// url 生成请求地址，userID 非空时通过 token 查询参数携带该用户的令牌
+func (f *streamFixture) url(t *testing.T, scheme, path, userID string, query url.Values) string {
+	t.Helper()
+
+	if query == nil {
+		query = url.Values{}
+	}
+	if userID != "" {
+		token, _, err := f.issuer.Issue(userID)
+		if err != nil {
+			t.Fatalf("issue token: %v", err)
+		}
+		query.Set("token", token)
+	}
+	u, _ := url.Parse(f.server.URL)
+	u.Scheme = scheme
+	u.Path = path
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// dial 以 userID 的身份连接游戏的 WebSocket
+func (f *streamFixture) dial(t *testing.T, userID string, query url.Values) *websocket.Conn {
+	t.Helper()
+
+	conn, resp, err := websocket.DefaultDialer.Dial(f.url(t, "ws", "/api/games/"+f.game.ID+"/ws", userID, query), nil)
+	if err != nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		t.Fatalf("dial: %v (status %d)", err, status)
+	}
+	t.Cleanup(func() {
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		conn.Close()
+	})
+	return conn
+}
+
+// wsMessage 推送消息和操作结果共用的字段
+type wsMessage struct {
+	Type        string          `json:"type"`
+	EventIndex  int             `json:"eventIndex"`
+	ActionIndex int             `json:"actionIndex"`
+	Data        json.RawMessage `json:"data"`
+	RequestID   string          `json:"requestId"`
+	Success     bool            `json:"success"`
+}
+
+// readWs 读取一条消息
+func readWs(t *testing.T, conn *websocket.Conn) wsMessage {
+	t.Helper()
+
+	var message wsMessage
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	return message
+}
+
+// readWsUntil 读取消息直到 match 返回 true
+func readWsUntil(t *testing.T, conn *websocket.Conn, match func(message wsMessage) bool) wsMessage {
+	t.Helper()
+
+	for {
+		if message := readWs(t, conn); match(message) {
+			return message
+		}
+	}
+}
+
+// isAction 判断消息是否为指定类型的动作
+func isAction(message wsMessage, actionType game.ActionType) bool {
+	if message.Type != "action" {
+		return false
+	}
+	var action game.GameAction
+	return json.Unmarshal(message.Data, &action) == nil && action.Type == actionType
+}
+
+// recordedAt 查找记录第 actionIndex 个动作的事件序号
+func recordedAt(g *game.Game, actionIndex int) int {
+	recorded := 0
+	for _, event := range g.GetEvents() {
+		if event.Type != game.EventActionRecorded {
+			continue
+		}
+		if recorded == actionIndex {
+			return event.Index
+		}
+		recorded++
+	}
+	return -1
+}
+
+// lastActionOf 获取最后一个指定类型动作的序号
+func lastActionOf(g *game.Game, actionType game.ActionType) int {
+	index := -1
+	g.View(func(g *game.Game) {
+		for i, action := range g.Actions {
+			if action.Type == actionType {
+				index = i
+			}
+		}
+	})
+	return index
+}
+
+func TestWebSocketPushesSnapshotAndActions(t *testing.T) {
+	f := newStreamFixture(t)
+	playerID := f.game.CurrentPlayerID
+	player := f.dial(t, playerID, nil)
+	spectator := f.dial(t, "", nil)
+
+	for _, conn := range []*websocket.Conn{player, spectator} {
+		snapshot := readWs(t, conn)
+		if snapshot.Type != "snapshot" || snapshot.ActionIndex != len(f.game.Actions)-1 {
+			t.Fatalf("got first message %s at action %d, want a snapshot at action %d",
+				snapshot.Type, snapshot.ActionIndex, len(f.game.Actions)-1)
+		}
+	}
+
+	// 观战者不能操作
+	if err := spectator.WriteJSON(map[string]string{"id": "s1", "action": "roll"}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if reply := readWs(t, spectator); reply.Type != "result" || reply.RequestID != "s1" || reply.Success {
+		t.Fatalf("got spectator reply %+v, want a failed result", reply)
+	}
+
+	if err := player.WriteJSON(map[string]string{"id": "p1", "action": "dance"}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if reply := readWs(t, player); reply.RequestID != "p1" || reply.Success {
+		t.Fatalf("got reply %+v to an unknown action, want a failed result", reply)
+	}
+
+	// 玩家的操作结果回复给玩家，产生的动作推送给所有连接
+	if err := player.WriteJSON(map[string]string{"id": "p2", "action": "roll"}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	reply := readWsUntil(t, player, func(message wsMessage) bool { return message.Type == "result" })
+	if reply.RequestID != "p2" || !reply.Success {
+		t.Fatalf("got roll reply %+v, want success", reply)
+	}
+	pushed := readWsUntil(t, spectator, func(message wsMessage) bool { return isAction(message, game.ActionRollDice) })
+	if want := lastActionOf(f.game, game.ActionRollDice); pushed.ActionIndex != want {
+		t.Fatalf("got roll pushed at action %d, want %d", pushed.ActionIndex, want)
+	}
+}
+
+func TestWebSocketResumesAfterLastAction(t *testing.T) {
+	f := newStreamFixture(t)
+	g := f.game
+	for i := 0; i < 3; i++ {
+		g.RollDice(g.CurrentPlayerID)
+		g.EndTurn(g.CurrentPlayerID)
+	}
+	if len(g.Actions) < 2 {
+		t.Fatalf("got %d actions, want at least 2", len(g.Actions))
+	}
+
+	// 从倒数第二个动作之后续传，先补发之后的全部事件
+	lastAction := len(g.Actions) - 2
+	after := recordedAt(g, lastAction)
+	conn := f.dial(t, "", url.Values{"lastAction": {strconv.Itoa(lastAction)}})
+
+	last := len(g.Events) - 1
+	for want := after + 1; want <= last; want++ {
+		message := readWs(t, conn)
+		if message.Type == "snapshot" || message.EventIndex != want {
+			t.Fatalf("got %s message at event %d, want event %d", message.Type, message.EventIndex, want)
+		}
+	}
+
+	if _, resp, err := websocket.DefaultDialer.Dial(f.url(t, "ws", "/api/games/"+g.ID+"/ws", "", url.Values{"lastAction": {"abc"}}), nil); err == nil || resp == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("invalid lastAction: got error %v, want status %d", err, http.StatusBadRequest)
+	}
+}
+
+func TestWebSocketHeartbeat(t *testing.T) {
+	pingPeriod := wsPingPeriod
+	t.Cleanup(func() { wsPingPeriod = pingPeriod })
+	wsPingPeriod = 20 * time.Millisecond
+
+	f := newStreamFixture(t)
+	conn := f.dial(t, "", nil)
+	pinged := make(chan struct{}, 1)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pinged <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-pinged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no heartbeat ping received")
+	}
+}
@@ -0,0 +1,143 @@
+// internal/audit/audit.go
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"monopoly/internal/storage"
+	"monopoly/pkg/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ActorSystem 服务自身执行的操作，如启动时授予管理员角色
+const ActorSystem = "system"
+
+// 管理操作类型
+const (
+	ActionMintCoins   = "mintCoins"   // 为用户增发金币
+	ActionBurnCoins   = "burnCoins"   // 销毁用户的金币
+	ActionSetRole     = "setRole"     // 修改用户角色
+	ActionDeleteUser  = "deleteUser"  // 删除用户
+	ActionEndGame     = "endGame"     // 强制结束游戏
+	ActionKickPlayer  = "kickPlayer"  // 将玩家踢出游戏
+	ActionViewLedger  = "viewLedger"  // 查询任意账户的账本分录
+	ActionCheckLedger = "checkLedger" // 账本一致性检查
+)
+
+// OutcomeFailed 操作失败，记录的 details.entry 为操作执行前写入的记录编号
+const OutcomeFailed = "failed"
+
+// Entry 一条审计记录
+// 管理操作执行前先写入记录，操作失败时再追加一条 Outcome 为 failed 的记录
+type Entry struct {
+	ID        int                    `json:"id"`
+	Actor     string                 `json:"actor"`  // 执行操作的用户
+	Action    string                 `json:"action"` // 操作类型，见 Action* 常量
+	Target    string                 `json:"target"` // 操作对象，如用户ID、游戏ID或账户
+	Reason    string                 `json:"reason"`
+	Outcome   string                 `json:"outcome,omitempty"` // 为空表示操作已执行，见 OutcomeFailed
+	Details   map[string]interface{} `json:"details,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// Filter 审计记录的查询条件，空字段不限制
+type Filter struct {
+	Actor  string
+	Action string
+	Target string
+}
+
+// matches 判断记录是否满足查询条件
+func (f Filter) matches(entry Entry) bool {
+	return (f.Actor == "" || entry.Actor == f.Actor) &&
+		(f.Action == "" || entry.Action == f.Action) &&
+		(f.Target == "" || entry.Target == f.Target)
+}
+
+// Log 管理操作的审计日志，记录只追加不修改
+type Log struct {
+	entries []Entry
+	store   storage.Store
+	mutex   sync.RWMutex
+}
+
+// NewLog 创建空的审计日志，每条记录先写入存储再生效
+func NewLog(store storage.Store) *Log {
+	return &Log{
+		entries: make([]Entry, 0),
+		store:   store,
+	}
+}
+
+// Load 从存储恢复全部审计记录，需在记录新操作之前调用
+func (l *Log) Load() error {
+	records, err := l.store.List(storage.CollectionAudit)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]Entry, 0, len(records))
+	for _, raw := range records {
+		var entry Entry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.entries = entries
+	return nil
+}
+
+// Record 追加一条审计记录，操作者、操作类型和原因不能为空
+// 记录编号和时间由审计日志分配
+func (l *Log) Record(entry Entry) (Entry, error) {
+	entry.Reason = strings.TrimSpace(entry.Reason)
+	if entry.Actor == "" || entry.Action == "" || entry.Reason == "" {
+		return Entry{}, utils.ErrInvalidInput
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	entry.ID = len(l.entries) + 1
+	entry.Timestamp = time.Now()
+	if err := l.store.Put(storage.CollectionAudit, fmt.Sprintf("%012d", entry.ID), entry); err != nil {
+		return Entry{}, err
+	}
+	l.entries = append(l.entries, entry)
+	return entry, nil
+}
+
+// RecordFailure 追加一条失败记录，说明先前写入的记录对应的操作没有完成
+func (l *Log) RecordFailure(entry Entry, cause error) (Entry, error) {
+	return l.Record(Entry{
+		Actor:   entry.Actor,
+		Action:  entry.Action,
+		Target:  entry.Target,
+		Reason:  entry.Reason,
+		Outcome: OutcomeFailed,
+		Details: map[string]interface{}{"entry": entry.ID, "error": cause.Error()},
+	})
+}
+
+// Entries 获取满足条件的审计记录，按记录顺序排列
+func (l *Log) Entries(filter Filter) []Entry {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	entries := make([]Entry, 0)
+	for _, entry := range l.entries {
+		if filter.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
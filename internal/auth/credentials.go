@@ -0,0 +1,107 @@
+// internal/auth/credentials.go
+package auth
+
+import (
+	"encoding/json"
+	"monopoly/internal/storage"
+	"monopoly/pkg/utils"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 密码长度限制，bcrypt 只使用前72字节
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// Credentials 用户的登录密码，只保存 bcrypt 哈希，与用户信息分开存储
+type Credentials struct {
+	hashes map[string][]byte
+	store  storage.Store
+	mutex  sync.RWMutex
+}
+
+// NewCredentials 创建密码存储，所有变更写入存储
+func NewCredentials(store storage.Store) *Credentials {
+	return &Credentials{
+		hashes: make(map[string][]byte),
+		store:  store,
+	}
+}
+
+// Load 从存储恢复密码哈希，需在处理请求之前调用
+func (c *Credentials) Load() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	records, err := c.store.List(storage.CollectionCredentials)
+	if err != nil {
+		return err
+	}
+	for userID, raw := range records {
+		var hash []byte
+		if err := json.Unmarshal(raw, &hash); err != nil {
+			return err
+		}
+		c.hashes[userID] = hash
+	}
+	return nil
+}
+
+// SetPassword 设置用户的登录密码
+func (c *Credentials) SetPassword(userID, password string) error {
+	if err := ValidatePassword(password); err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.store.Put(storage.CollectionCredentials, userID, hash); err != nil {
+		return err
+	}
+	c.hashes[userID] = hash
+	return nil
+}
+
+// Check 验证用户的登录密码，用户不存在或密码错误时返回同一个错误
+func (c *Credentials) Check(userID, password string) error {
+	c.mutex.RLock()
+	hash, exists := c.hashes[userID]
+	c.mutex.RUnlock()
+
+	if !exists {
+		return utils.ErrUnauthorized
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(password)); err != nil {
+		return utils.ErrUnauthorized
+	}
+	return nil
+}
+
+// Delete 删除用户的登录密码
+func (c *Credentials) Delete(userID string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.store.Delete(storage.CollectionCredentials, userID); err != nil {
+		return err
+	}
+	delete(c.hashes, userID)
+	return nil
+}
+
+// ValidatePassword 检查密码长度
+func ValidatePassword(password string) error {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return utils.ErrInvalidPassword
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// internal/auth/token_test.go
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"monopoly/pkg/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestIssuer 创建使用固定时钟的令牌签发器，返回可推进时钟的指针
+func newTestIssuer(secret string) (*Issuer, *time.Time) {
+	now := time.Unix(1_700_000_000, 0)
+	issuer := NewIssuer([]byte(secret), time.Hour)
+	issuer.now = func() time.Time { return now }
+	return issuer, &now
+}
+
+// signedWith 使用指定签发器为任意内容签名，用于构造签名有效但内容异常的令牌
+func signedWith(i *Issuer, payload string) string {
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(i.sign(encoded))
+}
+
+func TestVerifyAcceptsIssuedToken(t *testing.T) {
+	issuer, _ := newTestIssuer("secret")
+	token, issued, err := issuer.Issue("alice")
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+
+	claims, err := issuer.Verify(token)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if *claims != *issued {
+		t.Fatalf("got claims %+v, want %+v", claims, issued)
+	}
+}
+
+func TestVerifyRejectsInvalidTokens(t *testing.T) {
+	issuer, now := newTestIssuer("secret")
+	token, _, err := issuer.Issue("alice")
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	encoded, signature, _ := strings.Cut(token, ".")
+
+	other, _ := newTestIssuer("other-secret")
+	forged, _, err := other.Issue("alice")
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+
+	// 篡改身份后保留原签名
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin","iat":1700000000,"exp":1700003600}`))
+
+	// 翻转签名的最后一个字节
+	mac, _ := base64.RawURLEncoding.DecodeString(signature)
+	mac[len(mac)-1] ^= 0xff
+	flipped := base64.RawURLEncoding.EncodeToString(mac)
+
+	tests := []struct {
+		name  string
+		token string
+		after time.Duration // 验证前推进时钟的时长
+	}{
+		{"empty", "", 0},
+		{"missing separator", encoded + signature, 0},
+		{"empty signature", encoded + ".", 0},
+		{"non-base64 signature", encoded + ".!!!", 0},
+		{"non-base64 payload", "!!!." + signature, 0},
+		{"tampered payload", tampered + "." + signature, 0},
+		{"tampered signature", encoded + "." + flipped, 0},
+		{"extra segment", token + ".extra", 0},
+		{"wrong secret", forged, 0},
+		{"malformed claims", signedWith(issuer, "not json"), 0},
+		{"missing subject", signedWith(issuer, `{"iat":1700000000,"exp":1700003600}`), 0},
+		{"missing expiry", signedWith(issuer, `{"sub":"alice","iat":1700000000}`), 0},
+		{"expired", token, time.Hour},
+		{"long expired", token, 48 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := *now
+			defer func() { *now = saved }()
+			*now = now.Add(tt.after)
+
+			if claims, err := issuer.Verify(tt.token); !errors.Is(err, utils.ErrInvalidToken) {
+				t.Fatalf("got claims %+v and error %v, want %v", claims, err, utils.ErrInvalidToken)
+			}
+		})
+	}
+}
+
+func TestVerifyAcceptsTokenUntilExpiry(t *testing.T) {
+	issuer, now := newTestIssuer("secret")
+	token, _, err := issuer.Issue("alice")
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+
+	*now = now.Add(time.Hour - time.Second)
+	if _, err := issuer.Verify(token); err != nil {
+		t.Fatalf("verify one second before expiry: %v", err)
+	}
+}
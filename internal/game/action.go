@@ -2,7 +2,6 @@
 package game
 
 import (
-	"math/rand"
 	"monopoly/pkg/utils"
 	"time"
 )
@@ -13,10 +12,15 @@ type GameAction struct {
 	PlayerID  string     `json:"playerId"`
 	Position  int        `json:"position,omitempty"`
 	Amount    int        `json:"amount,omitempty"`
+	Dice      []int      `json:"dice,omitempty"` // 掷骰结果，用于客户端播放动画
+	TradeID   string     `json:"tradeId,omitempty"`
+	CardID    string     `json:"cardId,omitempty"`
+	InPrison  bool       `json:"inPrison,omitempty"` // 掷骰后玩家仍在狱中，本回合不再移动
 	Timestamp time.Time  `json:"timestamp"`
 }
 
 // RollDice 掷骰子并移动玩家
+// 掷出对子可以再掷一次，连续掷出 SpeedingDoubles 次对子时直接入狱；有未还债务时不能掷骰
 func (g *Game) RollDice(playerID string) (*GameAction, error) {
 	g.mutex.Lock()
 	defer g.mutex.Unlock()
@@ -30,36 +34,83 @@ func (g *Game) RollDice(playerID string) (*GameAction, error) {
 		return nil, utils.ErrAlreadyRolled
 	}
 
-	// 处理监狱状态
-	if err := g.handlePrisonState(player); err != nil {
-		return nil, err
+	if player.InDebt() {
+		return nil, utils.ErrOutstandingDebt
 	}
 
-	// 执行移动
-	steps := rand.Intn(6) + 1
+	// 处理监狱状态，掷出对子越狱时按越狱的点数移动且不再额外掷骰，未能出狱时返回越狱掷骰的结果
+	dice, stay := g.handlePrisonState(player)
+	if stay != nil {
+		return stay, nil
+	}
+	doubles := false
+	if dice == nil {
+		dice = g.rollDice(g.Settings.DiceCount)
+		doubles = isDoubles(dice)
+	}
+
+	// 连续掷出过多对子视为超速，直接入狱
 	oldPosition := player.Position
+	if doubles && g.Settings.SpeedingDoubles > 0 && player.DoublesRolled+1 >= g.Settings.SpeedingDoubles {
+		g.emit(&DiceRolled{
+			PlayerID: playerID,
+			Dice:     dice,
+			Doubles:  true,
+			From:     oldPosition,
+			To:       oldPosition,
+		})
+		action := &GameAction{
+			Type:      ActionSpeeding,
+			PlayerID:  playerID,
+			Dice:      dice,
+			Timestamp: g.now(),
+		}
+		// 入狱失败时也记录已经发生的掷骰
+		err := g.sendToPrison(player)
+		action.Position = player.Position
+		g.AddAction(action)
+		return action, err
+	}
+
+	// 执行移动
+	steps := 0
+	for _, value := range dice {
+		steps += value
+	}
 	newPosition := (oldPosition + steps) % len(g.Map.Tiles)
-	player.Position = newPosition
-	player.HasRolled = true
+	g.emit(&DiceRolled{
+		PlayerID: playerID,
+		Dice:     dice,
+		Doubles:  doubles,
+		From:     oldPosition,
+		To:       newPosition,
+	})
 
 	action := &GameAction{
 		Type:      ActionRollDice,
 		PlayerID:  playerID,
 		Position:  newPosition,
-		Timestamp: time.Now(),
+		Dice:      dice,
+		Timestamp: g.now(),
 	}
 
-	// 处理过起点奖励
+	// 处理过起点奖励和途经的银行
 	if newPosition < oldPosition {
 		g.handlePassingGo(player)
 	}
+	g.passBanks(player, oldPosition, newPosition)
 
-	// 处理新位置效果
-	if err := g.handleTileEffect(player); err != nil {
+	// 处理新位置效果，出错时同样记录已经发生的掷骰
+	err := g.handleTileEffect(player)
+	g.AddAction(action)
+	if err != nil {
 		return action, err // 返回动作但同时返回错误
 	}
 
-	g.Actions = append(g.Actions, action)
+	// 掷出对子且未因落点效果入狱、出局或欠债时可以再掷一次
+	if doubles && !player.InPrison && player.IsActive() && !player.InDebt() {
+		g.emit(&ExtraRollGranted{PlayerID: playerID})
+	}
 	return action, nil
 }
 
@@ -72,6 +123,9 @@ func (g *Game) BuyProperty(playerID string) (*GameAction, error) {
 		return nil, err
 	}
 
+	// 到期的拍卖先结算，拍卖进行中不能直接购买
+	g.resolveExpiredAuction()
+
 	player := g.Players[playerID]
 	tile := g.Map.Tiles[player.Position]
 
@@ -80,19 +134,18 @@ func (g *Game) BuyProperty(playerID string) (*GameAction, error) {
 	}
 
 	// 执行购买
-	player.Coins -= tile.Price
-	tile.OwnerID = playerID
-	g.PrizePool += tile.Price
+	g.transferCoins(playerID, AccountPrizePool, tile.Price, "buyProperty")
+	g.emit(&PropertyOwnerChanged{Position: player.Position, OwnerID: playerID})
 
 	action := &GameAction{
 		Type:      ActionBuyProperty,
 		PlayerID:  playerID,
 		Position:  player.Position,
 		Amount:    tile.Price,
-		Timestamp: time.Now(),
+		Timestamp: g.now(),
 	}
 
-	g.Actions = append(g.Actions, action)
+	g.AddAction(action)
 	return action, nil
 }
 
@@ -106,26 +159,83 @@ func (g *Game) UpgradeProperty(playerID string, position int) (*GameAction, erro
 	}
 
 	player := g.Players[playerID]
-	tile := g.Map.Tiles[position]
+	tile, err := g.Map.GetTile(position)
+	if err != nil {
+		return nil, err
+	}
 
 	if err := g.validatePropertyUpgrade(player, tile); err != nil {
 		return nil, err
 	}
 
-	upgradeCost := tile.Price / 2
-	player.Coins -= upgradeCost
-	tile.Level++
-	g.PrizePool += upgradeCost
+	upgradeCost := tile.GetUpgradeCost(g.Settings)
+	g.transferCoins(playerID, AccountPrizePool, upgradeCost, "upgrade")
+	g.emit(&PropertyLevelChanged{Position: position, Level: tile.Level + 1})
 
 	action := &GameAction{
 		Type:      ActionUpgrade,
 		PlayerID:  playerID,
 		Position:  position,
 		Amount:    upgradeCost,
-		Timestamp: time.Now(),
+		Timestamp: g.now(),
 	}
 
-	g.Actions = append(g.Actions, action)
+	g.AddAction(action)
+	return action, nil
+}
+
+// SellUpgrade 出售地产的一级升级，按升级费用的一半从奖池退款
+func (g *Game) SellUpgrade(playerID string, position int) (*GameAction, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if err := g.validateGameState(playerID); err != nil {
+		return nil, err
+	}
+
+	tile, err := g.Map.GetTile(position)
+	if err != nil {
+		return nil, err
+	}
+
+	if tile.Type != TileProperty {
+		return nil, utils.ErrNotProperty
+	}
+
+	if tile.OwnerID != playerID {
+		return nil, utils.ErrNotOwner
+	}
+
+	if tile.Level == 0 {
+		return nil, utils.ErrInvalidPropertyLevel
+	}
+
+	// 均衡升级规则下只能从组内等级最高的地产开始出售
+	if g.Settings.EvenBuild {
+		for _, t := range g.Map.GroupTiles(tile) {
+			if t.Level > tile.Level {
+				return nil, utils.ErrUnevenBuild
+			}
+		}
+	}
+
+	refund := tile.GetSellUpgradeRefund(g.Settings)
+	if g.PrizePool < refund {
+		return nil, utils.ErrInsufficientFunds
+	}
+
+	g.transferCoins(AccountPrizePool, playerID, refund, "sellUpgrade")
+	g.emit(&PropertyLevelChanged{Position: position, Level: tile.Level - 1})
+
+	action := &GameAction{
+		Type:      ActionSellUpgrade,
+		PlayerID:  playerID,
+		Position:  position,
+		Amount:    refund,
+		Timestamp: g.now(),
+	}
+
+	g.AddAction(action)
 	return action, nil
 }
 
@@ -138,18 +248,99 @@ func (g *Game) NextTurn() error {
 		return utils.ErrInvalidGameState
 	}
 
-	// 重置当前玩家状态
-	if currentPlayer := g.Players[g.CurrentPlayerID]; currentPlayer != nil {
-		currentPlayer.HasRolled = false
+	return g.nextTurn()
+}
+
+// EndTurn 当前玩家结束自己的回合
+func (g *Game) EndTurn(playerID string) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if err := g.validateGameState(playerID); err != nil {
+		return err
+	}
+
+	return g.nextTurn()
+}
+
+// CheckTimeouts 检查游戏和回合是否超时
+// 等待开始超时则放弃游戏，主持人暂停超时则恢复游戏，游戏超时则结束游戏，回合超时则自动进入下一个回合，
+// 自动操作会记录到动作日志
+func (g *Game) CheckTimeouts() error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	now := g.now()
+	if g.Status == StatusWaiting && now.Sub(g.CreatedAt) >= time.Duration(g.Settings.LobbyTimeout) {
+		g.emit(&GameAbandoned{})
+		return nil
+	}
+
+	if g.Status == StatusPaused && g.PauseReason == PauseHost && now.Sub(g.PausedAt) >= time.Duration(g.Settings.PauseTimeout) {
+		g.emit(&GameResumed{})
+		g.AddAction(&GameAction{
+			Type:      ActionPauseTimeout,
+			PlayerID:  g.CurrentPlayerID,
+			Timestamp: now,
+		})
+		return nil
 	}
 
-	// 获取排序后的玩家ID列表
+	if g.Status != StatusPlaying {
+		return nil
+	}
+
+	g.resolveExpiredAuction()
+
+	if now.Sub(g.StartTime) >= time.Duration(g.Settings.GameTimeout) {
+		g.AddAction(&GameAction{
+			Type:      ActionGameTimeout,
+			PlayerID:  g.CurrentPlayerID,
+			Timestamp: now,
+		})
+		return g.EndGame()
+	}
+
+	if now.Sub(g.CurrentTurnStarted) >= time.Duration(g.Settings.TurnTimeout) {
+		g.AddAction(&GameAction{
+			Type:      ActionTurnTimeout,
+			PlayerID:  g.CurrentPlayerID,
+			Timestamp: now,
+		})
+		return g.nextTurn()
+	}
+
+	return nil
+}
+
+// nextTurn 切换到下一位玩家，调用方需持有游戏锁
+// 当前玩家仍有未偿还的债务或到期贷款时先行结算，无力偿还则破产
+func (g *Game) nextTurn() error {
+	if current := g.Players[g.CurrentPlayerID]; current != nil && current.IsActive() {
+		g.settleDebt(current)
+		if current.IsActive() {
+			g.collectDueLoan(current)
+		}
+		if g.activePlayerCount() <= 1 {
+			return g.EndGame()
+		}
+	}
+
+	// 未处理的交易报价在回合结束时过期
+	g.expirePendingTrades()
+
+	// 当前玩家未购买停留的空地产时发起拍卖
+	g.maybeStartAuction()
+
+	// 按座位顺序切换到下一位玩家并重置当前玩家状态
 	playerIDs := g.getOrderedPlayerIDs()
-	g.CurrentPlayerID = g.getNextPlayerID(playerIDs)
-	g.CurrentTurnStarted = time.Now()
+	g.emit(&TurnChanged{
+		PreviousPlayerID: g.CurrentPlayerID,
+		PlayerID:         g.getNextPlayerID(playerIDs),
+	})
 
 	// 检查游戏是否应该结束
-	if time.Since(g.StartTime) >= GameTimeout {
+	if g.now().Sub(g.StartTime) >= time.Duration(g.Settings.GameTimeout) {
 		return g.EndGame()
 	}
 
@@ -185,6 +376,14 @@ func (g *Game) validatePropertyPurchase(player *Player, tile *Tile) error {
 		return utils.ErrPropertyOwned
 	}
 
+	if g.currentAuction() != nil {
+		return utils.ErrAuctionInProgress
+	}
+
+	if player.InDebt() {
+		return utils.ErrOutstandingDebt
+	}
+
 	if player.Coins < tile.Price {
 		return utils.ErrInsufficientFunds
 	}
@@ -202,11 +401,23 @@ func (g *Game) validatePropertyUpgrade(player *Player, tile *Tile) error {
 		return utils.ErrNotOwner
 	}
 
+	if tile.Mortgaged {
+		return utils.ErrPropertyMortgaged
+	}
+
 	if tile.Level >= len(tile.RentPrice)-1 {
 		return utils.ErrMaxLevel
 	}
 
-	upgradeCost := tile.Price / 2
+	if err := g.validateGroupBuild(player, tile); err != nil {
+		return err
+	}
+
+	if player.InDebt() {
+		return utils.ErrOutstandingDebt
+	}
+
+	upgradeCost := tile.GetUpgradeCost(g.Settings)
 	if player.Coins < upgradeCost {
 		return utils.ErrInsufficientFunds
 	}
@@ -214,45 +425,110 @@ func (g *Game) validatePropertyUpgrade(player *Player, tile *Tile) error {
 	return nil
 }
 
-// handlePrisonState 处理玩家的监狱状态
-func (g *Game) handlePrisonState(player *Player) error {
-	if player.InPrison {
-		if player.PrisonDays > 0 {
-			player.PrisonDays--
-			player.HasRolled = true
-			return utils.ErrInPrison
+// validateGroupBuild 验证地产组相关的升级规则
+func (g *Game) validateGroupBuild(player *Player, tile *Tile) error {
+	groupTiles := g.Map.GroupTiles(tile)
+
+	if g.Settings.RequireFullGroup {
+		if !g.Map.OwnsFullGroup(player.ID, tile) {
+			return utils.ErrGroupNotOwned
+		}
+		for _, t := range groupTiles {
+			if t.Mortgaged {
+				return utils.ErrPropertyMortgaged
+			}
+		}
+	}
+
+	// 均衡升级：不能比组内其他地产高出一级以上
+	if g.Settings.EvenBuild {
+		for _, t := range groupTiles {
+			if t.Level < tile.Level {
+				return utils.ErrUnevenBuild
+			}
 		}
-		player.InPrison = false
-		player.PrisonDays = 0
 	}
+
 	return nil
 }
 
-// handlePassingGo 处理经过起点奖励
-func (g *Game) handlePassingGo(player *Player) {
-	passingGoReward := int(float64(g.PrizePool) * PassingGoRewardRate)
-	player.Coins += passingGoReward
-	g.PrizePool -= passingGoReward
+// rollDice 按规则设置的面数掷 count 个骰子
+func (g *Game) rollDice(count int) []int {
+	dice := make([]int, count)
+	for i := range dice {
+		dice[i] = g.rng.Intn(g.Settings.DiceSides) + 1
+	}
+	return dice
 }
 
-// getOrderedPlayerIDs 获取排序后的玩家ID列表
-func (g *Game) getOrderedPlayerIDs() []string {
-	playerIDs := make([]string, 0, len(g.Players))
-	for id := range g.Players {
-		playerIDs = append(playerIDs, id)
+// isDoubles 检查是否掷出对子（两个及以上骰子点数全部相同）
+func isDoubles(dice []int) bool {
+	if len(dice) < 2 {
+		return false
+	}
+	for _, value := range dice[1:] {
+		if value != dice[0] {
+			return false
+		}
 	}
-	return playerIDs
+	return true
 }
 
-// getNextPlayerID 获取下一个玩家ID
-func (g *Game) getNextPlayerID(playerIDs []string) string {
-	for i, id := range playerIDs {
-		if id == g.CurrentPlayerID {
-			if i == len(playerIDs)-1 {
-				return playerIDs[0] // 回到第一个玩家
-			}
-			return playerIDs[i+1]
+// handlePrisonState 处理玩家的监狱状态
+// 刑期已满时出狱；越狱次数未用完时掷骰，掷出对子则出狱并返回骰子点数，
+// 否则度过一个回合并返回标记为仍在狱中的动作
+func (g *Game) handlePrisonState(player *Player) ([]int, *GameAction) {
+	if !player.InPrison {
+		return nil, nil
+	}
+
+	if player.PrisonDays <= 0 {
+		g.emit(&PrisonReleased{PlayerID: player.ID, Reason: "served"})
+		return nil, nil
+	}
+
+	if player.EscapeAttempts < g.Settings.MaxEscapeAttempts {
+		dice := g.rollDice(g.Settings.DiceCount)
+		escaped := isDoubles(dice)
+		g.emit(&EscapeAttempted{PlayerID: player.ID, Dice: dice})
+
+		action := &GameAction{
+			Type:      ActionEscapeRoll,
+			PlayerID:  player.ID,
+			Position:  player.Position,
+			Dice:      dice,
+			InPrison:  !escaped,
+			Timestamp: g.now(),
+		}
+		if escaped {
+			action.Amount = 1
 		}
+		g.AddAction(action)
+
+		if escaped {
+			g.emit(&PrisonReleased{PlayerID: player.ID, Reason: "doubles"})
+			return dice, nil
+		}
+
+		g.emit(&PrisonTurnServed{PlayerID: player.ID})
+		return nil, action
 	}
-	return playerIDs[0] // 如果找不到当前玩家（不应该发生），返回第一个玩家
+
+	g.emit(&PrisonTurnServed{PlayerID: player.ID})
+	action := &GameAction{
+		Type:      ActionPrisonTurn,
+		PlayerID:  player.ID,
+		Position:  player.Position,
+		InPrison:  true,
+		Timestamp: g.now(),
+	}
+	g.AddAction(action)
+	return nil, action
+}
+
+// handlePassingGo 处理经过起点奖励
+func (g *Game) handlePassingGo(player *Player) {
+	passingGoReward := int(float64(g.PrizePool) * g.Settings.PassingGoRewardRate)
+	g.transferCoins(AccountPrizePool, player.ID, passingGoReward, "passingGo")
+	g.payDepositInterest(player)
 }
@@ -0,0 +1,176 @@
+// internal/game/action_test.go
+package game
+
+import (
+	"errors"
+	"monopoly/pkg/utils"
+	"testing"
+)
+
+// peekDice 预测当前玩家下一次掷骰的结果，不影响游戏的随机数源
+func peekDice(g *Game) []int {
+	r := NewRandom(g.Seed)
+	r.advance(g.rng.Draws())
+
+	dice := make([]int, g.Settings.DiceCount)
+	for i := range dice {
+		dice[i] = r.Intn(g.Settings.DiceSides) + 1
+	}
+	return dice
+}
+
+// newTestGameWithDice 查找下一次掷骰满足 want 的种子，并以该种子创建游戏
+func newTestGameWithDice(t *testing.T, want func(dice []int) bool, ids ...string) *Game {
+	t.Helper()
+
+	for seed := int64(1); seed <= 1000; seed++ {
+		g := newTestGame(t, seed, ids...)
+		if want(peekDice(g)) {
+			return g
+		}
+	}
+	t.Fatal("no seed produces the wanted dice")
+	return nil
+}
+
+// countEvents 统计指定类型的事件数量
+func countEvents(g *Game, eventType EventType) int {
+	count := 0
+	for _, event := range g.Events {
+		if event.Type == eventType {
+			count++
+		}
+	}
+	return count
+}
+
+// findAction 查找最后一条指定类型的动作记录
+func findAction(g *Game, actionType ActionType) *GameAction {
+	for i := len(g.Actions) - 1; i >= 0; i-- {
+		if g.Actions[i].Type == actionType {
+			return g.Actions[i]
+		}
+	}
+	return nil
+}
+
+func TestRollDiceRejectsPlayerInDebt(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+	g.emit(&DebtIncurred{PlayerID: player.ID, CreditorID: AccountPrizePool, Amount: 100, Reason: "card:chance-fine"})
+
+	if _, err := g.RollDice(player.ID); !errors.Is(err, utils.ErrOutstandingDebt) {
+		t.Fatalf("got error %v, want %v", err, utils.ErrOutstandingDebt)
+	}
+	if count := countEvents(g, EventDiceRolled); count != 0 {
+		t.Fatalf("got %d dice rolls, want 0", count)
+	}
+}
+
+func TestRollDiceDoublesIntoDebtGrantsNoExtraRoll(t *testing.T) {
+	// 从起点掷出对子落在地产上：2、4、8、10号格
+	g := newTestGameWithDice(t, func(dice []int) bool {
+		return isDoubles(dice) && dice[0]+dice[1] != 6 && dice[0]+dice[1] != 12
+	}, "a", "b", "c")
+	player := g.Players[g.CurrentPlayerID]
+	owner := otherPlayer(g)
+
+	target := 0
+	for _, value := range peekDice(g) {
+		target += value
+	}
+	g.emit(&PropertyOwnerChanged{Position: target, OwnerID: owner.ID})
+	g.transferCoins(player.ID, AccountPrizePool, player.Coins, "test")
+
+	if _, err := g.RollDice(player.ID); err != nil {
+		t.Fatalf("roll dice: %v", err)
+	}
+	if len(player.Debts) != 1 || player.Debts[0].CreditorID != owner.ID {
+		t.Fatalf("got debts %+v, want rent owed to %s", player.Debts, owner.ID)
+	}
+	if count := countEvents(g, EventExtraRollGranted); count != 0 {
+		t.Fatalf("got %d extra rolls while in debt, want 0", count)
+	}
+	if _, err := g.RollDice(player.ID); !errors.Is(err, utils.ErrAlreadyRolled) {
+		t.Fatalf("got error %v, want %v", err, utils.ErrAlreadyRolled)
+	}
+	if action := findAction(g, ActionRollDice); action == nil || action.Position != target {
+		t.Fatalf("roll to %d not recorded: %+v", target, action)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestRollDiceSpeedingSendsToPrison(t *testing.T) {
+	g := newTestGameWithDice(t, isDoubles, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+
+	// 本回合已经连续掷出 SpeedingDoubles-1 次对子
+	for i := 1; i < g.Settings.SpeedingDoubles; i++ {
+		g.emit(&DiceRolled{PlayerID: player.ID, Dice: []int{1, 1}, Doubles: true})
+		g.emit(&ExtraRollGranted{PlayerID: player.ID})
+	}
+
+	action, err := g.RollDice(player.ID)
+	if err != nil {
+		t.Fatalf("roll dice: %v", err)
+	}
+	if action.Type != ActionSpeeding {
+		t.Fatalf("got action %s, want %s", action.Type, ActionSpeeding)
+	}
+	if !player.InPrison || player.Position != action.Position {
+		t.Fatalf("player not in prison at %d: in prison %v, position %d", action.Position, player.InPrison, player.Position)
+	}
+	if recorded := findAction(g, ActionSpeeding); recorded == nil || recorded.Position != action.Position {
+		t.Fatalf("speeding not recorded: %+v", recorded)
+	}
+	if count := countEvents(g, EventExtraRollGranted); count != g.Settings.SpeedingDoubles-1 {
+		t.Fatalf("got %d extra rolls, want %d", count, g.Settings.SpeedingDoubles-1)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestRollDiceFailedEscapeReturnsDice(t *testing.T) {
+	g := newTestGameWithDice(t, func(dice []int) bool { return !isDoubles(dice) }, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+	g.emit(&PrisonEntered{PlayerID: player.ID, Days: g.Settings.PrisonTurns})
+	dice := peekDice(g)
+
+	action, err := g.RollDice(player.ID)
+	if err != nil {
+		t.Fatalf("roll dice: %v", err)
+	}
+	if action.Type != ActionEscapeRoll || !action.InPrison || len(action.Dice) != len(dice) {
+		t.Fatalf("got action %+v, want a failed escape roll of %v", action, dice)
+	}
+	for i := range dice {
+		if action.Dice[i] != dice[i] {
+			t.Fatalf("got dice %v, want %v", action.Dice, dice)
+		}
+	}
+	if !player.InPrison || !player.HasRolled {
+		t.Fatalf("player left prison or can roll again: in prison %v, has rolled %v", player.InPrison, player.HasRolled)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestRollDiceWithoutEscapeAttemptsServesTurn(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+	g.emit(&PrisonEntered{PlayerID: player.ID, Days: g.Settings.PrisonTurns})
+	for i := 0; i < g.Settings.MaxEscapeAttempts; i++ {
+		g.emit(&EscapeAttempted{PlayerID: player.ID, Dice: []int{1, 2}})
+	}
+	days := player.PrisonDays
+
+	action, err := g.RollDice(player.ID)
+	if err != nil {
+		t.Fatalf("roll dice: %v", err)
+	}
+	if action.Type != ActionPrisonTurn || !action.InPrison || len(action.Dice) != 0 {
+		t.Fatalf("got action %+v, want a prison turn without dice", action)
+	}
+	if !player.InPrison || player.PrisonDays != days-1 {
+		t.Fatalf("got in prison %v with %d days, want %d days", player.InPrison, player.PrisonDays, days-1)
+	}
+	assertReplayMatches(t, g)
+}
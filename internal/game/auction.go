@@ -0,0 +1,234 @@
+// internal/game/auction.go
+package game
+
+import (
+	"fmt"
+	"monopoly/pkg/utils"
+	"sort"
+	"time"
+)
+
+// AuctionStatus 拍卖状态
+type AuctionStatus string
+
+const (
+	AuctionOpen   AuctionStatus = "open"
+	AuctionSold   AuctionStatus = "sold"
+	AuctionUnsold AuctionStatus = "unsold"
+)
+
+// AuctionBid 拍卖出价
+type AuctionBid struct {
+	PlayerID  string    `json:"playerId"`
+	Amount    int       `json:"amount"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Auction 对未被购买地产的限时拍卖
+type Auction struct {
+	ID         string        `json:"id"`
+	Position   int           `json:"position"`
+	Status     AuctionStatus `json:"status"`
+	StartedAt  time.Time     `json:"startedAt"`
+	EndsAt     time.Time     `json:"endsAt"`
+	Bids       []AuctionBid  `json:"bids"`
+	WinnerID   string        `json:"winnerId,omitempty"`
+	WinningBid int           `json:"winningBid,omitempty"`
+}
+
+// Clone 创建拍卖的深拷贝
+func (a *Auction) Clone() *Auction {
+	clone := *a
+	clone.Bids = append([]AuctionBid(nil), a.Bids...)
+	return &clone
+}
+
+// HighestBid 获取当前最高出价
+func (a *Auction) HighestBid() int {
+	highest := 0
+	for _, bid := range a.Bids {
+		if bid.Amount > highest {
+			highest = bid.Amount
+		}
+	}
+	return highest
+}
+
+// DeclineProperty 放弃购买当前位置的地产并发起拍卖
+func (g *Game) DeclineProperty(playerID string) (*Auction, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if err := g.validateGameState(playerID); err != nil {
+		return nil, err
+	}
+
+	if !g.Settings.AuctionsEnabled {
+		return nil, utils.ErrActionNotAllowed
+	}
+
+	player := g.Players[playerID]
+	tile := g.Map.Tiles[player.Position]
+	if tile.Type != TileProperty {
+		return nil, utils.ErrNotProperty
+	}
+
+	if tile.OwnerID != "" {
+		return nil, utils.ErrPropertyOwned
+	}
+
+	if player.InDebt() {
+		return nil, utils.ErrOutstandingDebt
+	}
+
+	g.resolveExpiredAuction()
+	if g.currentAuction() != nil {
+		return nil, utils.ErrAuctionInProgress
+	}
+
+	return g.startAuction(tile.ID).Clone(), nil
+}
+
+// PlaceBid 对进行中的拍卖出价，出价不能超过玩家当前金币
+func (g *Game) PlaceBid(playerID string, amount int) (*Auction, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.Status != StatusPlaying {
+		return nil, utils.ErrInvalidGameState
+	}
+
+	g.resolveExpiredAuction()
+	auction := g.currentAuction()
+	if auction == nil {
+		return nil, utils.ErrNoAuction
+	}
+
+	player := g.Players[playerID]
+	if player == nil {
+		return nil, utils.ErrPlayerNotFound
+	}
+
+	if !player.IsActive() {
+		return nil, utils.ErrPlayerBankrupt
+	}
+
+	if player.InDebt() {
+		return nil, utils.ErrOutstandingDebt
+	}
+
+	if amount <= auction.HighestBid() {
+		return nil, utils.ErrBidTooLow
+	}
+
+	if player.Coins < amount {
+		return nil, utils.ErrInsufficientFunds
+	}
+
+	g.emit(&AuctionBidPlaced{AuctionID: auction.ID, PlayerID: playerID, Amount: amount})
+	return auction.Clone(), nil
+}
+
+// GetAuction 获取进行中或最近一次的拍卖
+func (g *Game) GetAuction() (*Auction, error) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	if len(g.Auctions) == 0 {
+		return nil, utils.ErrNoAuction
+	}
+	return g.Auctions[len(g.Auctions)-1].Clone(), nil
+}
+
+// currentAuction 获取进行中的拍卖
+func (g *Game) currentAuction() *Auction {
+	if len(g.Auctions) == 0 {
+		return nil
+	}
+	if auction := g.Auctions[len(g.Auctions)-1]; auction.Status == AuctionOpen {
+		return auction
+	}
+	return nil
+}
+
+// startAuction 发起拍卖
+func (g *Game) startAuction(position int) *Auction {
+	now := g.now()
+	g.emit(&AuctionStarted{
+		AuctionID: fmt.Sprintf("auction-%d", len(g.Auctions)+1),
+		Position:  position,
+		EndsAt:    now.Add(time.Duration(g.Settings.AuctionDuration)),
+	})
+	return g.Auctions[len(g.Auctions)-1]
+}
+
+// maybeStartAuction 回合结束时，若当前玩家停留在本回合未购买的空地产上则发起拍卖
+func (g *Game) maybeStartAuction() {
+	if !g.Settings.AuctionsEnabled || g.currentAuction() != nil {
+		return
+	}
+
+	player := g.Players[g.CurrentPlayerID]
+	if player == nil || !player.IsActive() || !player.HasRolled {
+		return
+	}
+
+	tile := g.Map.Tiles[player.Position]
+	if tile.Type != TileProperty || tile.OwnerID != "" {
+		return
+	}
+
+	// 本回合已经拍卖过该地产
+	if len(g.Auctions) > 0 {
+		last := g.Auctions[len(g.Auctions)-1]
+		if last.Position == tile.ID && !last.StartedAt.Before(g.CurrentTurnStarted) {
+			return
+		}
+	}
+
+	g.startAuction(tile.ID)
+}
+
+// resolveExpiredAuction 结算已到期的拍卖
+func (g *Game) resolveExpiredAuction() {
+	auction := g.currentAuction()
+	if auction == nil || g.now().Before(auction.EndsAt) {
+		return
+	}
+	g.closeAuction(auction)
+}
+
+// closeAuction 结算拍卖：出价从高到低，第一个仍有能力支付的玩家获得地产
+func (g *Game) closeAuction(auction *Auction) {
+	bids := append([]AuctionBid(nil), auction.Bids...)
+	sort.SliceStable(bids, func(i, j int) bool {
+		return bids[i].Amount > bids[j].Amount
+	})
+
+	tile := g.Map.Tiles[auction.Position]
+	for _, bid := range bids {
+		player := g.Players[bid.PlayerID]
+		if tile.OwnerID != "" || !player.IsActive() || player.InDebt() || player.Coins < bid.Amount {
+			continue
+		}
+
+		g.transferCoins(player.ID, AccountPrizePool, bid.Amount, "auction")
+		g.emit(&PropertyOwnerChanged{Position: auction.Position, OwnerID: player.ID})
+		g.emit(&AuctionClosed{AuctionID: auction.ID, WinnerID: player.ID, Amount: bid.Amount})
+		g.AddAction(&GameAction{
+			Type:      ActionAuction,
+			PlayerID:  player.ID,
+			Position:  auction.Position,
+			Amount:    bid.Amount,
+			Timestamp: g.now(),
+		})
+		return
+	}
+
+	g.emit(&AuctionClosed{AuctionID: auction.ID})
+	g.AddAction(&GameAction{
+		Type:      ActionAuction,
+		Position:  auction.Position,
+		Timestamp: g.now(),
+	})
+}
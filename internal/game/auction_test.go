@@ -0,0 +1,81 @@
+// internal/game/auction_test.go
+package game
+
+import (
+	"errors"
+	"monopoly/pkg/utils"
+	"testing"
+	"time"
+)
+
+// newAuctionTestGame 创建开启拍卖的游戏，当前玩家停在1号空地产上
+func newAuctionTestGame(t *testing.T) (*Game, *Player) {
+	t.Helper()
+
+	settings := DefaultSettings()
+	settings.AuctionsEnabled = true
+	g := newTestGameWithSettings(t, settings, 1, "a", "b", "c")
+
+	player := g.Players[g.CurrentPlayerID]
+	g.emit(&PlayerMoved{PlayerID: player.ID, From: player.Position, To: 1})
+	return g, player
+}
+
+func TestBuyPropertyRejectedDuringAuction(t *testing.T) {
+	g, player := newAuctionTestGame(t)
+
+	if _, err := g.DeclineProperty(player.ID); err != nil {
+		t.Fatalf("decline property: %v", err)
+	}
+	if _, err := g.BuyProperty(player.ID); !errors.Is(err, utils.ErrAuctionInProgress) {
+		t.Fatalf("got error %v, want %v", err, utils.ErrAuctionInProgress)
+	}
+	if owner := g.Map.Tiles[1].OwnerID; owner != "" {
+		t.Fatalf("property bought by %s during auction", owner)
+	}
+}
+
+func TestBuyPropertyAfterAuctionExpired(t *testing.T) {
+	g, player := newAuctionTestGame(t)
+	bidder := otherPlayer(g)
+
+	if _, err := g.DeclineProperty(player.ID); err != nil {
+		t.Fatalf("decline property: %v", err)
+	}
+	if _, err := g.PlaceBid(bidder.ID, 150); err != nil {
+		t.Fatalf("place bid: %v", err)
+	}
+
+	// 到期的拍卖在购买前结算，地产归出价最高者
+	advanceClock(g, time.Duration(g.Settings.AuctionDuration))
+	if _, err := g.BuyProperty(player.ID); !errors.Is(err, utils.ErrPropertyOwned) {
+		t.Fatalf("got error %v, want %v", err, utils.ErrPropertyOwned)
+	}
+	if owner := g.Map.Tiles[1].OwnerID; owner != bidder.ID {
+		t.Fatalf("property owned by %q, want highest bidder %s", owner, bidder.ID)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestDeclinePropertyRejectsPlayerInDebt(t *testing.T) {
+	g, player := newAuctionTestGame(t)
+	g.emit(&DebtIncurred{PlayerID: player.ID, CreditorID: AccountPrizePool, Amount: 100, Reason: "card:chance-fine"})
+
+	if _, err := g.DeclineProperty(player.ID); !errors.Is(err, utils.ErrOutstandingDebt) {
+		t.Fatalf("got error %v, want %v", err, utils.ErrOutstandingDebt)
+	}
+	if len(g.Auctions) != 0 {
+		t.Fatalf("got %d auctions, want 0", len(g.Auctions))
+	}
+}
+
+func TestDeclinePropertyRejectedDuringAuction(t *testing.T) {
+	g, player := newAuctionTestGame(t)
+
+	if _, err := g.DeclineProperty(player.ID); err != nil {
+		t.Fatalf("decline property: %v", err)
+	}
+	if _, err := g.DeclineProperty(player.ID); !errors.Is(err, utils.ErrAuctionInProgress) {
+		t.Fatalf("got error %v, want %v", err, utils.ErrAuctionInProgress)
+	}
+}
@@ -0,0 +1,231 @@
+// internal/game/bank.go
+package game
+
+import (
+	"monopoly/pkg/utils"
+	"strings"
+)
+
+// depositAccountPrefix 存款账户标识前缀，资金转移中以 "@deposit:<玩家ID>" 表示玩家的银行存款
+const depositAccountPrefix = "@deposit:"
+
+// Loan 玩家的银行贷款，本金从奖池发放
+type Loan struct {
+	Principal int `json:"principal"`
+	Balance   int `json:"balance"` // 尚需偿还的本息
+	DueTurn   int `json:"dueTurn"` // 玩家第 DueTurn 个回合结束时到期
+}
+
+// depositAccount 获取玩家的存款账户标识
+func depositAccount(playerID string) string {
+	return depositAccountPrefix + playerID
+}
+
+// TakeLoan 向银行贷款，本息需在到期回合结束前还清
+func (g *Game) TakeLoan(playerID string, amount int) (*GameAction, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	player, err := g.validateBankAccess(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if player.Loan != nil {
+		return nil, utils.ErrLoanOutstanding
+	}
+
+	if amount <= 0 || amount > g.Settings.MaxLoanAmount {
+		return nil, utils.ErrInvalidInput
+	}
+
+	if g.PrizePool < amount {
+		return nil, utils.ErrInsufficientFunds
+	}
+
+	g.emit(&LoanTaken{
+		PlayerID:  playerID,
+		Principal: amount,
+		Balance:   amount + int(float64(amount)*g.Settings.LoanInterestRate),
+		DueTurn:   player.TurnsTaken + g.Settings.LoanTermTurns,
+	})
+	g.transferCoins(AccountPrizePool, playerID, amount, "loan")
+
+	return g.recordBankAction(ActionLoan, player, amount), nil
+}
+
+// RepayLoan 偿还贷款，amount 为0时还清全部本息
+func (g *Game) RepayLoan(playerID string, amount int) (*GameAction, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	player, err := g.validateBankAccess(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if player.Loan == nil {
+		return nil, utils.ErrNoLoan
+	}
+
+	if amount == 0 {
+		amount = player.Loan.Balance
+	}
+	if amount < 0 || amount > player.Loan.Balance {
+		return nil, utils.ErrInvalidInput
+	}
+
+	if player.Coins < amount {
+		return nil, utils.ErrInsufficientFunds
+	}
+
+	g.repayLoan(player, amount)
+	return g.recordBankAction(ActionRepayLoan, player, amount), nil
+}
+
+// Deposit 将金币存入银行，每次经过起点获得利息
+func (g *Game) Deposit(playerID string, amount int) (*GameAction, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	player, err := g.validateBankAccess(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if player.InDebt() {
+		return nil, utils.ErrOutstandingDebt
+	}
+
+	if amount <= 0 {
+		return nil, utils.ErrInvalidInput
+	}
+
+	if player.Coins < amount {
+		return nil, utils.ErrInsufficientFunds
+	}
+
+	g.transferCoins(playerID, depositAccount(playerID), amount, "deposit")
+	return g.recordBankAction(ActionDeposit, player, amount), nil
+}
+
+// Withdraw 从银行取出存款，amount 为0时全部取出
+func (g *Game) Withdraw(playerID string, amount int) (*GameAction, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	player, err := g.validateBankAccess(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if amount == 0 {
+		amount = player.Deposit
+	}
+	if amount <= 0 || amount > player.Deposit {
+		return nil, utils.ErrInvalidInput
+	}
+
+	g.transferCoins(depositAccount(playerID), playerID, amount, "withdraw")
+	return g.recordBankAction(ActionWithdraw, player, amount), nil
+}
+
+// validateBankAccess 验证玩家本回合停留或经过了银行
+func (g *Game) validateBankAccess(playerID string) (*Player, error) {
+	if err := g.validateGameState(playerID); err != nil {
+		return nil, err
+	}
+
+	player := g.Players[playerID]
+	if !player.AtBank {
+		return nil, utils.ErrNotAtBank
+	}
+	return player, nil
+}
+
+// recordBankAction 记录银行业务动作
+func (g *Game) recordBankAction(actionType ActionType, player *Player, amount int) *GameAction {
+	action := &GameAction{
+		Type:      actionType,
+		PlayerID:  player.ID,
+		Position:  player.Position,
+		Amount:    amount,
+		Timestamp: g.now(),
+	}
+	g.AddAction(action)
+	return action
+}
+
+// visitBank 玩家停留或经过银行，本回合内可办理银行业务
+func (g *Game) visitBank(player *Player) {
+	if !player.AtBank {
+		g.emit(&BankVisited{PlayerID: player.ID})
+	}
+}
+
+// passBanks 处理玩家从 from 向前移动到 to 途中经过的银行，不含终点
+func (g *Game) passBanks(player *Player, from, to int) {
+	size := len(g.Map.Tiles)
+	for pos := (from + 1) % size; pos != to; pos = (pos + 1) % size {
+		if g.Map.Tiles[pos].Type == TileBank {
+			g.visitBank(player)
+			return
+		}
+	}
+}
+
+// repayLoan 偿还贷款，调用方需确保余额充足
+func (g *Game) repayLoan(player *Player, amount int) {
+	g.transferCoins(player.ID, AccountPrizePool, amount, "repayLoan")
+	g.emit(&LoanRepaid{PlayerID: player.ID, Amount: amount})
+}
+
+// collectDueLoan 回合结束时收回到期贷款，余额不足则转为欠奖池的债务并进入破产结算
+func (g *Game) collectDueLoan(player *Player) {
+	loan := player.Loan
+	if loan == nil || player.TurnsTaken < loan.DueTurn {
+		return
+	}
+
+	if balance := loan.Balance; player.Coins >= balance {
+		g.repayLoan(player, balance)
+		g.recordBankAction(ActionRepayLoan, player, balance)
+		return
+	}
+
+	g.emit(&LoanDefaulted{PlayerID: player.ID})
+	g.settleDebt(player)
+}
+
+// payDepositInterest 经过起点时按存款余额支付利息
+func (g *Game) payDepositInterest(player *Player) {
+	interest := int(float64(player.Deposit) * g.Settings.DepositInterestRate)
+	if interest > g.PrizePool {
+		interest = g.PrizePool
+	}
+	g.transferCoins(AccountPrizePool, depositAccount(player.ID), interest, "depositInterest")
+}
+
+// closeBankAccount 游戏结束时取回存款并尽可能偿还贷款，未还清的部分计入负债
+func (g *Game) closeBankAccount(player *Player) {
+	g.transferCoins(depositAccount(player.ID), player.ID, player.Deposit, "withdraw")
+
+	if player.Loan != nil {
+		if amount := min(player.Coins, player.Loan.Balance); amount > 0 {
+			g.repayLoan(player, amount)
+		}
+	}
+}
+
+// loanBalance 获取玩家尚未偿还的贷款本息
+func (p *Player) loanBalance() int {
+	if p.Loan == nil {
+		return 0
+	}
+	return p.Loan.Balance
+}
+
+// DepositOwner 解析存款账户对应的玩家ID
+func DepositOwner(account string) (string, bool) {
+	return strings.CutPrefix(account, depositAccountPrefix)
+}
@@ -0,0 +1,206 @@
+// internal/game/bank_test.go
+package game
+
+import (
+	"errors"
+	"monopoly/pkg/utils"
+	"testing"
+)
+
+// playUntilTurn 双方轮流直接结束回合，直到轮到 player 开始第 turn 个回合
+func playUntilTurn(t *testing.T, g *Game, player *Player, turn int) {
+	t.Helper()
+
+	for player.TurnsTaken < turn || g.CurrentPlayerID != player.ID {
+		if err := g.EndTurn(g.CurrentPlayerID); err != nil {
+			t.Fatalf("end turn: %v", err)
+		}
+	}
+}
+
+func TestBankRequiresVisit(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+
+	if _, err := g.TakeLoan(player.ID, 1000); !errors.Is(err, utils.ErrNotAtBank) {
+		t.Fatalf("loan: got error %v, want %v", err, utils.ErrNotAtBank)
+	}
+	if _, err := g.Deposit(player.ID, 1000); !errors.Is(err, utils.ErrNotAtBank) {
+		t.Fatalf("deposit: got error %v, want %v", err, utils.ErrNotAtBank)
+	}
+
+	// 经过银行格后本回合可以办理业务
+	g.passBanks(player, 8, 10)
+	if !player.AtBank {
+		t.Fatal("passing the bank did not grant bank access")
+	}
+	if _, err := g.Deposit(player.ID, 1000); err != nil {
+		t.Fatalf("deposit after passing the bank: %v", err)
+	}
+
+	if err := g.EndTurn(player.ID); err != nil {
+		t.Fatalf("end turn: %v", err)
+	}
+	if player.AtBank {
+		t.Fatal("bank access kept after the turn ended")
+	}
+}
+
+func TestTakeAndRepayLoan(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+	g.visitBank(player)
+	coins, prizePool := player.Coins, g.PrizePool
+
+	if _, err := g.TakeLoan(player.ID, g.Settings.MaxLoanAmount+1); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("loan above the limit: got error %v, want %v", err, utils.ErrInvalidInput)
+	}
+	if _, err := g.TakeLoan(player.ID, 1000); err != nil {
+		t.Fatalf("take loan: %v", err)
+	}
+	want := Loan{Principal: 1000, Balance: 1100, DueTurn: player.TurnsTaken + g.Settings.LoanTermTurns}
+	if player.Loan == nil || *player.Loan != want {
+		t.Fatalf("got loan %+v, want %+v", player.Loan, want)
+	}
+	if player.Coins != coins+1000 || g.PrizePool != prizePool-1000 {
+		t.Fatalf("got coins %d and prize pool %d, want 1000 lent from the prize pool", player.Coins, g.PrizePool)
+	}
+	if _, err := g.TakeLoan(player.ID, 500); !errors.Is(err, utils.ErrLoanOutstanding) {
+		t.Fatalf("second loan: got error %v, want %v", err, utils.ErrLoanOutstanding)
+	}
+
+	if _, err := g.RepayLoan(player.ID, 100); err != nil {
+		t.Fatalf("repay part: %v", err)
+	}
+	if player.Loan == nil || player.Loan.Balance != 1000 {
+		t.Fatalf("got loan %+v after repaying 100, want balance 1000", player.Loan)
+	}
+	if _, err := g.RepayLoan(player.ID, 0); err != nil {
+		t.Fatalf("repay rest: %v", err)
+	}
+	if player.Loan != nil || player.Coins != coins-100 || g.PrizePool != prizePool+100 {
+		t.Fatalf("got loan %+v, coins %d and prize pool %d, want the loan closed with 100 interest paid",
+			player.Loan, player.Coins, g.PrizePool)
+	}
+	if _, err := g.RepayLoan(player.ID, 0); !errors.Is(err, utils.ErrNoLoan) {
+		t.Fatalf("repay without loan: got error %v, want %v", err, utils.ErrNoLoan)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestDepositEarnsInterestWhenPassingGo(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+	g.visitBank(player)
+	coins := player.Coins
+
+	if _, err := g.Deposit(player.ID, coins+1); !errors.Is(err, utils.ErrInsufficientFunds) {
+		t.Fatalf("deposit more than owned: got error %v, want %v", err, utils.ErrInsufficientFunds)
+	}
+	if _, err := g.Deposit(player.ID, 1000); err != nil {
+		t.Fatalf("deposit: %v", err)
+	}
+	if player.Deposit != 1000 || player.Coins != coins-1000 {
+		t.Fatalf("got deposit %d and coins %d, want 1000 moved to the bank", player.Deposit, player.Coins)
+	}
+
+	// 经过起点按存款余额获得利息，利息留在存款中
+	prizePool := g.PrizePool
+	reward := int(float64(prizePool) * g.Settings.PassingGoRewardRate)
+	g.handlePassingGo(player)
+	if player.Deposit != 1050 || player.Coins != coins-1000+reward {
+		t.Fatalf("got deposit %d and coins %d, want deposit 1050 and coins %d", player.Deposit, player.Coins, coins-1000+reward)
+	}
+
+	if _, err := g.Withdraw(player.ID, 2000); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("withdraw more than deposited: got error %v, want %v", err, utils.ErrInvalidInput)
+	}
+	if _, err := g.Withdraw(player.ID, 0); err != nil {
+		t.Fatalf("withdraw all: %v", err)
+	}
+	if player.Deposit != 0 || player.Coins != coins+50+reward {
+		t.Fatalf("got deposit %d and coins %d, want everything withdrawn", player.Deposit, player.Coins)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestDueLoanCollectedAtEndOfTurn(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+	g.visitBank(player)
+	if _, err := g.TakeLoan(player.ID, 1000); err != nil {
+		t.Fatalf("take loan: %v", err)
+	}
+	due := player.Loan.DueTurn
+
+	playUntilTurn(t, g, player, due)
+	if player.Loan == nil {
+		t.Fatal("loan collected before the due turn ended")
+	}
+	coins := player.Coins
+	if err := g.EndTurn(player.ID); err != nil {
+		t.Fatalf("end turn: %v", err)
+	}
+	if player.Loan != nil || player.Coins != coins-1100 {
+		t.Fatalf("got loan %+v and coins %d, want 1100 collected", player.Loan, player.Coins)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestUnpaidLoanLeadsToBankruptcy(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b", "c")
+	player := g.Players[g.CurrentPlayerID]
+	g.visitBank(player)
+	if _, err := g.TakeLoan(player.ID, 1000); err != nil {
+		t.Fatalf("take loan: %v", err)
+	}
+	g.emit(&PropertyOwnerChanged{Position: 1, OwnerID: player.ID})
+	playUntilTurn(t, g, player, player.Loan.DueTurn)
+	g.transferCoins(player.ID, AccountPrizePool, player.Coins-500, "test")
+
+	if err := g.EndTurn(player.ID); err != nil {
+		t.Fatalf("end turn: %v", err)
+	}
+	if countEvents(g, EventLoanDefaulted) != 1 || !player.IsBankrupt() {
+		t.Fatalf("got status %s after defaulting, want bankrupt", player.Status)
+	}
+	if player.Loan != nil || player.Coins != 0 || g.Map.Tiles[1].OwnerID != "" {
+		t.Fatalf("got loan %+v, coins %d and tile owner %q, want assets returned to the bank",
+			player.Loan, player.Coins, g.Map.Tiles[1].OwnerID)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestFinalResultsSubtractUnpaidLoan(t *testing.T) {
+	settings := DefaultSettings()
+	settings.PayoutScheme = PayoutWinnerTakesAll
+	g := newTestGameWithSettings(t, settings, 1, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+	g.visitBank(player)
+	if _, err := g.TakeLoan(player.ID, 1000); err != nil {
+		t.Fatalf("take loan: %v", err)
+	}
+	if _, err := g.Deposit(player.ID, 200); err != nil {
+		t.Fatalf("deposit: %v", err)
+	}
+	g.transferCoins(player.ID, AccountPrizePool, player.Coins-300, "test")
+
+	// 结束时取回200存款，连同300现金偿还贷款，剩余600本息计入负债
+	if err := g.ForceEnd(); err != nil {
+		t.Fatalf("force end: %v", err)
+	}
+	results, err := g.GetFinalResults()
+	if err != nil {
+		t.Fatalf("final results: %v", err)
+	}
+	for _, result := range results {
+		if result.PlayerID != player.ID {
+			continue
+		}
+		if result.FinalCoins != 0 || result.LoanBalance != 600 || result.TotalAssets != -600 {
+			t.Fatalf("got result %+v, want no coins and 600 loan balance counted against assets", result)
+		}
+		return
+	}
+	t.Fatalf("player %s missing from results %+v", player.ID, results)
+}
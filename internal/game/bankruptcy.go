@@ -0,0 +1,170 @@
+// internal/game/bankruptcy.go
+package game
+
+import (
+	"monopoly/pkg/utils"
+)
+
+// PayDebt 偿还所有未还债务
+func (g *Game) PayDebt(playerID string) (*GameAction, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if err := g.validateGameState(playerID); err != nil {
+		return nil, err
+	}
+
+	player := g.Players[playerID]
+	if !player.InDebt() {
+		return nil, utils.ErrNoDebt
+	}
+
+	if player.Coins < player.DebtTotal() {
+		return nil, utils.ErrInsufficientFunds
+	}
+
+	return g.payDebt(player), nil
+}
+
+// DeclareBankruptcy 宣告破产，资产归债权人所有，玩家出局
+func (g *Game) DeclareBankruptcy(playerID string) (*GameAction, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if err := g.validateGameState(playerID); err != nil {
+		return nil, err
+	}
+
+	action := g.bankrupt(g.Players[playerID])
+	if g.activePlayerCount() <= 1 {
+		return action, g.EndGame()
+	}
+
+	return action, g.nextTurn()
+}
+
+// incurDebt 玩家无力支付时记录债务，玩家可在回合结束前变卖资产筹款
+// 已有债务时追加一笔，原有债务和债权人保持不变
+func (g *Game) incurDebt(player *Player, creditorID string, amount int, reason string) {
+	g.emit(&DebtIncurred{
+		PlayerID:   player.ID,
+		CreditorID: creditorID,
+		Amount:     amount,
+		Reason:     reason,
+	})
+}
+
+// payDebt 按产生的先后偿还所有债务，调用方需确保余额充足
+func (g *Game) payDebt(player *Player) *GameAction {
+	total := 0
+	for _, debt := range player.Debts {
+		g.transferCoins(player.ID, debt.CreditorID, debt.Amount, debt.Reason)
+		total += debt.Amount
+	}
+	g.emit(&DebtSettled{PlayerID: player.ID})
+
+	action := &GameAction{
+		Type:      ActionPayDebt,
+		PlayerID:  player.ID,
+		Amount:    total,
+		Timestamp: g.now(),
+	}
+	g.AddAction(action)
+	return action
+}
+
+// settleDebt 结算债务，余额不足以还清所有债务时破产
+func (g *Game) settleDebt(player *Player) {
+	if !player.InDebt() {
+		return
+	}
+
+	if player.Coins >= player.DebtTotal() {
+		g.payDebt(player)
+		return
+	}
+
+	g.bankrupt(player)
+}
+
+// bankrupt 执行破产：现金（含存款）按债务产生的先后依次偿还各债权人，
+// 地产、持有的卡片和剩余现金归剩余资产的债权人（见 residualCreditor）；该债权人为玩家时转移给该玩家，
+// 否则地产归还银行、持有的卡片放回牌堆底部、金币进入奖池
+func (g *Game) bankrupt(player *Player) *GameAction {
+	creditorID := residualCreditor(player)
+	_, creditorIsPlayer := g.Players[creditorID]
+
+	for _, tile := range g.Map.Tiles {
+		if tile.OwnerID != player.ID {
+			continue
+		}
+
+		if creditorIsPlayer {
+			g.emit(&PropertyOwnerChanged{Position: tile.ID, OwnerID: creditorID})
+			continue
+		}
+
+		if tile.Level > 0 {
+			g.emit(&PropertyLevelChanged{Position: tile.ID, Level: 0})
+		}
+		if tile.Mortgaged {
+			g.emit(&PropertyMortgageChanged{Position: tile.ID, Mortgaged: false})
+		}
+		g.emit(&PropertyOwnerChanged{Position: tile.ID, OwnerID: ""})
+	}
+
+	for _, card := range append([]HeldCard(nil), player.HeldCards...) {
+		returned := &CardReturned{PlayerID: player.ID, Deck: card.Deck, CardID: card.CardID}
+		if creditorIsPlayer {
+			returned.ToPlayerID = creditorID
+		}
+		g.emit(returned)
+	}
+
+	// 存款先取出，与现金一并偿还债务，未还清的贷款随破产一笔勾销
+	g.transferCoins(depositAccount(player.ID), player.ID, player.Deposit, "withdraw")
+
+	amount := player.Coins
+	for _, debt := range player.Debts {
+		g.transferCoins(player.ID, debt.CreditorID, min(debt.Amount, player.Coins), "bankruptcy")
+	}
+	g.transferCoins(player.ID, creditorID, player.Coins, "bankruptcy")
+	g.emit(&PlayerBankrupt{PlayerID: player.ID, CreditorID: creditorID})
+
+	action := &GameAction{
+		Type:      ActionBankrupt,
+		PlayerID:  player.ID,
+		Amount:    amount,
+		Timestamp: g.now(),
+	}
+	g.AddAction(action)
+	return action
+}
+
+// residualCreditor 获取破产玩家剩余资产的归属：现金按先后偿还债务后第一位未能足额受偿的债权人，
+// 现金足以还清所有债务时为最早一笔债务的债权人，没有债务时为银行
+func residualCreditor(player *Player) string {
+	if !player.InDebt() {
+		return AccountPrizePool
+	}
+
+	cash := player.Coins + player.Deposit
+	for _, debt := range player.Debts {
+		if cash < debt.Amount {
+			return debt.CreditorID
+		}
+		cash -= debt.Amount
+	}
+	return player.Debts[0].CreditorID
+}
+
+// activePlayerCount 获取仍在游戏中的玩家数量
+func (g *Game) activePlayerCount() int {
+	count := 0
+	for _, player := range g.Players {
+		if player.IsActive() {
+			count++
+		}
+	}
+	return count
+}
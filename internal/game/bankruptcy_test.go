@@ -0,0 +1,234 @@
+// internal/game/bankruptcy_test.go
+package game
+
+import (
+	"errors"
+	"monopoly/pkg/utils"
+	"testing"
+)
+
+// holdCard 让玩家抽到并保留一张卡片
+func holdCard(g *Game, player *Player, deck, cardID string) {
+	g.shuffleDeck(deck)
+	g.emit(&CardDrawn{PlayerID: player.ID, Deck: deck, CardID: cardID, Effect: CardGetOutOfPrison})
+	g.emit(&CardHeld{PlayerID: player.ID, Deck: deck, CardID: cardID})
+}
+
+func TestPayDebt(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b", "c")
+	player := g.Players[g.CurrentPlayerID]
+	creditor := otherPlayer(g)
+
+	if _, err := g.PayDebt(player.ID); !errors.Is(err, utils.ErrNoDebt) {
+		t.Fatalf("got error %v, want %v", err, utils.ErrNoDebt)
+	}
+
+	g.emit(&DebtIncurred{PlayerID: player.ID, CreditorID: creditor.ID, Amount: player.Coins + 1, Reason: "rent"})
+	if _, err := g.PayDebt(player.ID); !errors.Is(err, utils.ErrInsufficientFunds) {
+		t.Fatalf("got error %v, want %v", err, utils.ErrInsufficientFunds)
+	}
+
+	g.emit(&DebtSettled{PlayerID: player.ID})
+	g.emit(&DebtIncurred{PlayerID: player.ID, CreditorID: creditor.ID, Amount: 300, Reason: "rent"})
+	coins, creditorCoins := player.Coins, creditor.Coins
+	if _, err := g.PayDebt(player.ID); err != nil {
+		t.Fatalf("pay debt: %v", err)
+	}
+	if player.InDebt() {
+		t.Fatal("debt not cleared")
+	}
+	if player.Coins != coins-300 || creditor.Coins != creditorCoins+300 {
+		t.Fatalf("got coins %d/%d, want %d/%d", player.Coins, creditor.Coins, coins-300, creditorCoins+300)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestEndTurnSettlesDebt(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b", "c")
+	player := g.Players[g.CurrentPlayerID]
+	creditor := otherPlayer(g)
+	g.emit(&DebtIncurred{PlayerID: player.ID, CreditorID: creditor.ID, Amount: 300, Reason: "rent"})
+	coins := player.Coins
+
+	if err := g.EndTurn(player.ID); err != nil {
+		t.Fatalf("end turn: %v", err)
+	}
+	if player.InDebt() || player.Coins != coins-300 {
+		t.Fatalf("debt not paid at end of turn: debts %+v, coins %d", player.Debts, player.Coins)
+	}
+	if !player.IsActive() {
+		t.Fatal("player went bankrupt despite having enough coins")
+	}
+}
+
+func TestSecondDebtInTurnIsKept(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b", "c")
+	player := g.Players[g.CurrentPlayerID]
+	creditor := otherPlayer(g)
+	g.incurDebt(player, creditor.ID, 300, "rent")
+	g.incurDebt(player, AccountPrizePool, 100, "card:chance-fine")
+
+	if len(player.Debts) != 2 || player.DebtTotal() != 400 {
+		t.Fatalf("got debts %+v, want 300 rent and 100 fine", player.Debts)
+	}
+	if player.Debts[0].CreditorID != creditor.ID || player.Debts[1].CreditorID != AccountPrizePool {
+		t.Fatalf("got debts %+v, want creditors %s then the prize pool", player.Debts, creditor.ID)
+	}
+
+	coins, creditorCoins, prizePool := player.Coins, creditor.Coins, g.PrizePool
+	if err := g.EndTurn(player.ID); err != nil {
+		t.Fatalf("end turn: %v", err)
+	}
+	if player.InDebt() || player.Coins != coins-400 {
+		t.Fatalf("debts not paid at end of turn: debts %+v, coins %d", player.Debts, player.Coins)
+	}
+	if creditor.Coins != creditorCoins+300 || g.PrizePool != prizePool+100 {
+		t.Fatalf("got creditor coins %d and prize pool %d, want %d and %d",
+			creditor.Coins, g.PrizePool, creditorCoins+300, prizePool+100)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestBankruptcyPaysCreditorsInOrder(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b", "c")
+	player := g.Players[g.CurrentPlayerID]
+	first := otherPlayer(g)
+	var second *Player
+	for _, p := range g.Players {
+		if p != player && p != first {
+			second = p
+		}
+	}
+	g.emit(&PropertyOwnerChanged{Position: 1, OwnerID: player.ID})
+	g.incurDebt(player, first.ID, player.Coins-50, "rent")
+	g.incurDebt(player, second.ID, 200, "rent")
+	coins, firstCoins, secondCoins := player.Coins, first.Coins, second.Coins
+
+	if err := g.EndTurn(player.ID); err != nil {
+		t.Fatalf("end turn: %v", err)
+	}
+	if !player.IsBankrupt() || player.InDebt() || player.Coins != 0 {
+		t.Fatalf("got status %s, debts %+v and coins %d, want bankrupt with nothing left", player.Status, player.Debts, player.Coins)
+	}
+
+	// 第一笔债务足额偿还，剩余现金和地产归未能足额受偿的第二位债权人
+	if first.Coins != firstCoins+coins-50 {
+		t.Fatalf("first creditor got %d coins, want %d", first.Coins-firstCoins, coins-50)
+	}
+	if second.Coins != secondCoins+50 {
+		t.Fatalf("second creditor got %d coins, want 50", second.Coins-secondCoins)
+	}
+	if owner := g.Map.Tiles[1].OwnerID; owner != second.ID {
+		t.Fatalf("property owned by %q, want second creditor %s", owner, second.ID)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestBankruptcyLeavesLaterCreditorsUnpaidWhenFirstIsShort(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b", "c")
+	player := g.Players[g.CurrentPlayerID]
+	creditor := otherPlayer(g)
+	g.emit(&PropertyOwnerChanged{Position: 1, OwnerID: player.ID})
+	g.incurDebt(player, creditor.ID, player.Coins+100, "rent")
+	g.incurDebt(player, AccountPrizePool, 100, "card:chance-fine")
+	coins, creditorCoins, prizePool := player.Coins, creditor.Coins, g.PrizePool
+
+	if err := g.EndTurn(player.ID); err != nil {
+		t.Fatalf("end turn: %v", err)
+	}
+	if !player.IsBankrupt() {
+		t.Fatalf("got player status %s, want bankrupt", player.Status)
+	}
+	if creditor.Coins != creditorCoins+coins || g.PrizePool != prizePool {
+		t.Fatalf("got creditor coins %d and prize pool %d, want all %d coins paid to %s",
+			creditor.Coins, g.PrizePool, coins, creditor.ID)
+	}
+	if owner := g.Map.Tiles[1].OwnerID; owner != creditor.ID {
+		t.Fatalf("property owned by %q, want creditor %s", owner, creditor.ID)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestEndTurnBankruptsPlayerInDebt(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b", "c")
+	player := g.Players[g.CurrentPlayerID]
+	creditor := otherPlayer(g)
+	g.emit(&PropertyOwnerChanged{Position: 1, OwnerID: player.ID})
+	holdCard(g, player, DeckChance, "chance-release")
+	g.emit(&DebtIncurred{PlayerID: player.ID, CreditorID: creditor.ID, Amount: player.Coins + 1, Reason: "rent"})
+	coins, creditorCoins := player.Coins, creditor.Coins
+
+	if err := g.EndTurn(player.ID); err != nil {
+		t.Fatalf("end turn: %v", err)
+	}
+	if !player.IsBankrupt() {
+		t.Fatalf("got player status %s, want bankrupt", player.Status)
+	}
+	if g.CurrentPlayerID == player.ID {
+		t.Fatal("turn did not advance past bankrupt player")
+	}
+
+	// 债权人为玩家时获得全部资产
+	if owner := g.Map.Tiles[1].OwnerID; owner != creditor.ID {
+		t.Fatalf("property owned by %q, want creditor %s", owner, creditor.ID)
+	}
+	if player.Coins != 0 || creditor.Coins != creditorCoins+coins {
+		t.Fatalf("got coins %d/%d, want 0/%d", player.Coins, creditor.Coins, creditorCoins+coins)
+	}
+	if len(player.HeldCards) != 0 {
+		t.Fatalf("bankrupt player still holds %v", player.HeldCards)
+	}
+	if len(creditor.HeldCards) != 1 || creditor.HeldCards[0].CardID != "chance-release" {
+		t.Fatalf("creditor holds %v, want chance-release", creditor.HeldCards)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestDeclareBankruptcyReturnsAssetsToBank(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b", "c")
+	player := g.Players[g.CurrentPlayerID]
+	g.emit(&PropertyOwnerChanged{Position: 1, OwnerID: player.ID})
+	g.emit(&PropertyLevelChanged{Position: 1, Level: 1})
+	holdCard(g, player, DeckChance, "chance-release")
+	prizePool := g.PrizePool
+	coins := player.Coins
+
+	if _, err := g.DeclareBankruptcy(player.ID); err != nil {
+		t.Fatalf("declare bankruptcy: %v", err)
+	}
+	if !player.IsBankrupt() {
+		t.Fatalf("got player status %s, want bankrupt", player.Status)
+	}
+
+	tile := g.Map.Tiles[1]
+	if tile.OwnerID != "" || tile.Level != 0 {
+		t.Fatalf("property not returned to bank: owner %q, level %d", tile.OwnerID, tile.Level)
+	}
+	if g.PrizePool != prizePool+coins {
+		t.Fatalf("got prize pool %d, want %d", g.PrizePool, prizePool+coins)
+	}
+
+	// 持有的卡片放回牌堆底部，不再被破产玩家占用
+	if len(player.HeldCards) != 0 {
+		t.Fatalf("bankrupt player still holds %v", player.HeldCards)
+	}
+	pile := g.DrawPiles[DeckChance]
+	if len(pile) != len(g.Map.Decks[DeckChance]) || pile[len(pile)-1] != "chance-release" {
+		t.Fatalf("got draw pile %v, want chance-release at the bottom of a full deck", pile)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestDeclareBankruptcyEndsGameWithOnePlayerLeft(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+
+	if _, err := g.DeclareBankruptcy(g.CurrentPlayerID); err != nil {
+		t.Fatalf("declare bankruptcy: %v", err)
+	}
+	if g.Status != StatusFinished {
+		t.Fatalf("got status %s, want %s", g.Status, StatusFinished)
+	}
+	if g.PrizePool != 0 {
+		t.Fatalf("prize pool %d not paid out", g.PrizePool)
+	}
+}
@@ -0,0 +1,279 @@
+// internal/game/card.go
+package game
+
+import (
+	"fmt"
+	"monopoly/pkg/utils"
+)
+
+// 牌堆名称，分别对应机会格和命运格
+const (
+	DeckChance = "chance"
+	DeckFate   = "fate"
+)
+
+// CardEffect 卡片效果类型
+type CardEffect string
+
+const (
+	CardMoveTo             CardEffect = "moveTo"             // 移动到 Target 格，绕过起点时领取过路奖励
+	CardMoveSteps          CardEffect = "moveSteps"          // 前进 Steps 步，负数表示后退
+	CardCollect            CardEffect = "collect"            // 从奖池领取 Amount 加奖池的 Rate 比例
+	CardPay                CardEffect = "pay"                // 向奖池支付 Amount，不足时记为债务
+	CardCollectFromPlayers CardEffect = "collectFromPlayers" // 其他每位玩家支付 Amount，余额不足者付清为止
+	CardPayPlayers         CardEffect = "payPlayers"         // 向其他每位玩家支付 Amount，余额不足时付清为止
+	CardGoToPrison         CardEffect = "goToPrison"         // 直接进入监狱
+	CardGetOutOfPrison     CardEffect = "getOutOfPrison"     // 出狱许可证，由玩家保留直到使用
+	CardRepairs            CardEffect = "repairs"            // 按地产数量和升级等级向奖池支付维修费
+)
+
+// Card 卡片定义，由地图文件的 decks 字段给出
+type Card struct {
+	ID          string     `json:"id"`
+	Text        string     `json:"text"`
+	Effect      CardEffect `json:"effect"`
+	Amount      int        `json:"amount,omitempty"`
+	Rate        float64    `json:"rate,omitempty"`
+	Target      int        `json:"target,omitempty"`
+	Steps       int        `json:"steps,omitempty"`
+	PerProperty int        `json:"perProperty,omitempty"`
+	PerLevel    int        `json:"perLevel,omitempty"`
+}
+
+// HeldCard 玩家持有的卡片
+type HeldCard struct {
+	Deck   string `json:"deck"`
+	CardID string `json:"cardId"`
+}
+
+// deckForTile 获取地块对应的牌堆名称
+func deckForTile(tileType TileType) string {
+	switch tileType {
+	case TileChance:
+		return DeckChance
+	case TileFate:
+		return DeckFate
+	}
+	return ""
+}
+
+// findCard 在牌堆定义中查找卡片
+func (m *GameMap) findCard(deck, cardID string) *Card {
+	for _, card := range m.Decks[deck] {
+		if card.ID == cardID {
+			return card
+		}
+	}
+	return nil
+}
+
+// drawCard 从牌堆顶抽一张卡并执行效果，牌堆抽完后重新洗牌
+func (g *Game) drawCard(player *Player, deck string) error {
+	if len(g.DrawPiles[deck]) == 0 {
+		g.shuffleDeck(deck)
+	}
+	pile := g.DrawPiles[deck]
+	if len(pile) == 0 {
+		// 所有卡片都被玩家持有
+		return nil
+	}
+
+	card := g.Map.findCard(deck, pile[0])
+	g.emit(&CardDrawn{PlayerID: player.ID, Deck: deck, CardID: card.ID, Effect: card.Effect})
+
+	// 效果执行出错时同样记录已经抽到的卡片
+	amount, err := g.applyCard(player, deck, card)
+	g.AddAction(&GameAction{
+		Type:      ActionDrawCard,
+		PlayerID:  player.ID,
+		Position:  player.Position,
+		Amount:    amount,
+		CardID:    card.ID,
+		Timestamp: g.now(),
+	})
+	return err
+}
+
+// shuffleDeck 使用游戏随机数源洗牌，玩家持有的卡片不参与洗牌
+func (g *Game) shuffleDeck(deck string) {
+	held := make(map[string]bool)
+	for _, id := range g.TurnOrder {
+		for _, card := range g.Players[id].HeldCards {
+			if card.Deck == deck {
+				held[card.CardID] = true
+			}
+		}
+	}
+
+	order := make([]string, 0, len(g.Map.Decks[deck]))
+	for _, card := range g.Map.Decks[deck] {
+		if !held[card.ID] {
+			order = append(order, card.ID)
+		}
+	}
+	g.rng.Shuffle(len(order), func(i, j int) {
+		order[i], order[j] = order[j], order[i]
+	})
+
+	g.emit(&DeckShuffled{Deck: deck, Order: order})
+}
+
+// applyCard 执行卡片效果，返回玩家金币的净变化
+func (g *Game) applyCard(player *Player, deck string, card *Card) (int, error) {
+	reason := "card:" + card.ID
+
+	switch card.Effect {
+	case CardMoveTo:
+		return 0, g.moveByCard(player, card.Target, true)
+
+	case CardMoveSteps:
+		size := len(g.Map.Tiles)
+		target := ((player.Position+card.Steps)%size + size) % size
+		return 0, g.moveByCard(player, target, card.Steps > 0)
+
+	case CardCollect:
+		amount := card.Amount + int(float64(g.PrizePool)*card.Rate)
+		if amount > g.PrizePool {
+			amount = g.PrizePool
+		}
+		g.transferCoins(AccountPrizePool, player.ID, amount, reason)
+		return amount, nil
+
+	case CardPay:
+		paid, err := g.payToPool(player, card.Amount, reason)
+		return -paid, err
+
+	case CardRepairs:
+		fee := 0
+		for _, tile := range g.Map.Tiles {
+			if tile.OwnerID == player.ID {
+				fee += card.PerProperty + card.PerLevel*tile.Level
+			}
+		}
+		paid, err := g.payToPool(player, fee, reason)
+		return -paid, err
+
+	case CardCollectFromPlayers:
+		total := 0
+		for _, id := range g.TurnOrder {
+			other := g.Players[id]
+			if other.ID == player.ID || !other.IsActive() {
+				continue
+			}
+			amount := min(card.Amount, other.Coins)
+			g.transferCoins(other.ID, player.ID, amount, reason)
+			total += amount
+		}
+		return total, nil
+
+	case CardPayPlayers:
+		total := 0
+		for _, id := range g.TurnOrder {
+			other := g.Players[id]
+			if other.ID == player.ID || !other.IsActive() {
+				continue
+			}
+			amount := min(card.Amount, player.Coins)
+			g.transferCoins(player.ID, other.ID, amount, reason)
+			total += amount
+		}
+		return -total, nil
+
+	case CardGoToPrison:
+		return 0, g.sendToPrison(player)
+
+	case CardGetOutOfPrison:
+		g.emit(&CardHeld{PlayerID: player.ID, Deck: deck, CardID: card.ID})
+	}
+	return 0, nil
+}
+
+// payToPool 向奖池支付，余额不足时记为债务，返回实际支付的金额
+func (g *Game) payToPool(player *Player, amount int, reason string) (int, error) {
+	if amount <= 0 {
+		return 0, nil
+	}
+	if player.Coins < amount {
+		g.incurDebt(player, AccountPrizePool, amount, reason)
+		return 0, nil
+	}
+	g.transferCoins(player.ID, AccountPrizePool, amount, reason)
+	return amount, nil
+}
+
+// moveByCard 按卡片效果移动玩家并处理落点效果，向前移动时处理途经的起点和银行
+// 落点为机会或命运格时不再连续抽卡
+func (g *Game) moveByCard(player *Player, target int, forward bool) error {
+	from := player.Position
+	g.emit(&PlayerMoved{PlayerID: player.ID, From: from, To: target})
+	if forward {
+		if target < from {
+			g.handlePassingGo(player)
+		}
+		g.passBanks(player, from, target)
+	}
+
+	if deckForTile(g.Map.Tiles[target].Type) != "" {
+		return nil
+	}
+	return g.handleTileEffect(player)
+}
+
+// sendToPrison 将玩家移动到监狱格并入狱，地图没有监狱格时原地入狱
+func (g *Game) sendToPrison(player *Player) error {
+	for _, tile := range g.Map.Tiles {
+		if tile.Type == TilePrison {
+			if player.Position != tile.ID {
+				g.emit(&PlayerMoved{PlayerID: player.ID, From: player.Position, To: tile.ID})
+			}
+			break
+		}
+	}
+	return g.handlePrison(player)
+}
+
+// validateDecks 验证卡片定义：地图上的机会格和命运格必须有对应的非空牌堆
+func (m *GameMap) validateDecks() error {
+	for _, tile := range m.Tiles {
+		if deck := deckForTile(tile.Type); deck != "" && len(m.Decks[deck]) == 0 {
+			return fmt.Errorf("%w: tile %d requires a non-empty %q deck", utils.ErrInvalidMap, tile.ID, deck)
+		}
+	}
+
+	for deck, cards := range m.Decks {
+		if deck != DeckChance && deck != DeckFate {
+			return fmt.Errorf("%w: unknown deck %q", utils.ErrInvalidMap, deck)
+		}
+
+		ids := make(map[string]bool)
+		for _, card := range cards {
+			if card == nil || card.ID == "" || ids[card.ID] {
+				return fmt.Errorf("%w: deck %q has a missing or duplicate card id", utils.ErrInvalidMap, deck)
+			}
+			ids[card.ID] = true
+
+			if card.Amount < 0 || card.PerProperty < 0 || card.PerLevel < 0 || card.Rate < 0 || card.Rate > 1 {
+				return fmt.Errorf("%w: card %q has an invalid amount", utils.ErrInvalidMap, card.ID)
+			}
+
+			switch card.Effect {
+			case CardMoveTo:
+				if card.Target < 0 || card.Target >= len(m.Tiles) {
+					return fmt.Errorf("%w: card %q targets unknown tile %d", utils.ErrInvalidMap, card.ID, card.Target)
+				}
+				if deckForTile(m.Tiles[card.Target].Type) != "" {
+					return fmt.Errorf("%w: card %q must not target a card tile", utils.ErrInvalidMap, card.ID)
+				}
+			case CardMoveSteps:
+				if card.Steps == 0 {
+					return fmt.Errorf("%w: card %q must move a non-zero number of steps", utils.ErrInvalidMap, card.ID)
+				}
+			case CardCollect, CardPay, CardCollectFromPlayers, CardPayPlayers,
+				CardGoToPrison, CardGetOutOfPrison, CardRepairs:
+			default:
+				return fmt.Errorf("%w: card %q has unknown effect %q", utils.ErrInvalidMap, card.ID, card.Effect)
+			}
+		}
+	}
+	return nil
+}
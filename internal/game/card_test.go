@@ -0,0 +1,173 @@
+// internal/game/card_test.go
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+// stackDeck 将牌堆设置为指定的顺序，第一张位于牌堆顶
+func stackDeck(g *Game, deck string, order ...string) {
+	g.emit(&DeckShuffled{Deck: deck, Order: order})
+}
+
+// drawnCards 获取事件日志中指定牌堆被抽到的卡片
+func drawnCards(g *Game, deck string) []string {
+	cards := make([]string, 0)
+	for _, event := range g.Events {
+		if drawn, ok := event.Payload.(*CardDrawn); ok && drawn.Deck == deck {
+			cards = append(cards, drawn.CardID)
+		}
+	}
+	return cards
+}
+
+func TestDeckDrawsWithoutReplacementUntilReshuffle(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+	size := len(g.Map.Decks[DeckFate])
+
+	for i := 0; i < size; i++ {
+		g.drawCard(player, DeckFate)
+	}
+	drawn := drawnCards(g, DeckFate)
+	if count := countEvents(g, EventDeckShuffled); count != 1 {
+		t.Fatalf("got %d shuffles for one pass through the deck, want 1", count)
+	}
+	want := make([]string, 0, size)
+	for _, card := range g.Map.Decks[DeckFate] {
+		want = append(want, card.ID)
+	}
+	got := slices.Clone(drawn)
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Fatalf("first pass drew %v, want every card once", drawn)
+	}
+
+	// 出狱许可证被玩家保留，重新洗牌时不再放回牌堆
+	g.drawCard(player, DeckFate)
+	if count := countEvents(g, EventDeckShuffled); count != 2 {
+		t.Fatalf("got %d shuffles after the deck ran out, want 2", count)
+	}
+	if pile := g.DrawPiles[DeckFate]; len(pile) != size-2 || slices.Contains(pile, "fate-release") {
+		t.Fatalf("got draw pile %v, want %d cards without the held fate-release", pile, size-2)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestCardEffects(t *testing.T) {
+	tests := []struct {
+		deck   string
+		cardID string
+		check  func(t *testing.T, g *Game, player, other *Player, coins, otherCoins, prizePool int)
+	}{
+		{DeckChance, "chance-bonus", func(t *testing.T, g *Game, player, other *Player, coins, otherCoins, prizePool int) {
+			if player.Coins != coins+100 || g.PrizePool != prizePool-100 {
+				t.Fatalf("got coins %d and prize pool %d, want 100 from the prize pool", player.Coins, g.PrizePool)
+			}
+		}},
+		{DeckChance, "chance-fine", func(t *testing.T, g *Game, player, other *Player, coins, otherCoins, prizePool int) {
+			if player.Coins != coins-100 || g.PrizePool != prizePool+100 {
+				t.Fatalf("got coins %d and prize pool %d, want 100 paid to the prize pool", player.Coins, g.PrizePool)
+			}
+		}},
+		{DeckChance, "chance-repairs", func(t *testing.T, g *Game, player, other *Player, coins, otherCoins, prizePool int) {
+			// 两块地产各20，一级升级50
+			if player.Coins != coins-90 {
+				t.Fatalf("got coins %d, want %d", player.Coins, coins-90)
+			}
+		}},
+		{DeckChance, "chance-back", func(t *testing.T, g *Game, player, other *Player, coins, otherCoins, prizePool int) {
+			if player.Position != 0 || player.Coins != coins {
+				t.Fatalf("got position %d and coins %d, want 0 without passing-go reward", player.Position, player.Coins)
+			}
+		}},
+		{DeckChance, "chance-go", func(t *testing.T, g *Game, player, other *Player, coins, otherCoins, prizePool int) {
+			if player.Position != 0 || player.Coins <= coins {
+				t.Fatalf("got position %d and coins %d, want 0 with passing-go reward", player.Position, player.Coins)
+			}
+		}},
+		{DeckChance, "chance-prison", func(t *testing.T, g *Game, player, other *Player, coins, otherCoins, prizePool int) {
+			if !player.InPrison || g.Map.Tiles[player.Position].Type != TilePrison {
+				t.Fatalf("got position %d and in prison %v, want in prison", player.Position, player.InPrison)
+			}
+		}},
+		{DeckFate, "fate-birthday", func(t *testing.T, g *Game, player, other *Player, coins, otherCoins, prizePool int) {
+			if player.Coins != coins+100 || other.Coins != otherCoins-100 {
+				t.Fatalf("got coins %d/%d, want 100 from the other player", player.Coins, other.Coins)
+			}
+		}},
+		{DeckFate, "fate-treat", func(t *testing.T, g *Game, player, other *Player, coins, otherCoins, prizePool int) {
+			if player.Coins != coins-50 || other.Coins != otherCoins+50 {
+				t.Fatalf("got coins %d/%d, want 50 paid to the other player", player.Coins, other.Coins)
+			}
+		}},
+		{DeckFate, "fate-release", func(t *testing.T, g *Game, player, other *Player, coins, otherCoins, prizePool int) {
+			if len(player.HeldCards) != 1 || player.HeldCards[0] != (HeldCard{Deck: DeckFate, CardID: "fate-release"}) {
+				t.Fatalf("player holds %v, want fate-release", player.HeldCards)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cardID, func(t *testing.T) {
+			g := newTestGame(t, 1, "a", "b")
+			player := g.Players[g.CurrentPlayerID]
+			other := otherPlayer(g)
+			g.emit(&PlayerMoved{PlayerID: player.ID, From: 0, To: 3})
+			g.emit(&PropertyOwnerChanged{Position: 1, OwnerID: player.ID})
+			g.emit(&PropertyOwnerChanged{Position: 2, OwnerID: player.ID})
+			g.emit(&PropertyLevelChanged{Position: 2, Level: 1})
+			stackDeck(g, tt.deck, tt.cardID)
+			coins, otherCoins, prizePool := player.Coins, other.Coins, g.PrizePool
+
+			if err := g.drawCard(player, tt.deck); err != nil {
+				t.Fatalf("draw card: %v", err)
+			}
+			if action := findAction(g, ActionDrawCard); action == nil || action.CardID != tt.cardID {
+				t.Fatalf("got draw action %+v, want card %s", action, tt.cardID)
+			}
+			tt.check(t, g, player, other, coins, otherCoins, prizePool)
+			assertReplayMatches(t, g)
+		})
+	}
+}
+
+func TestCardPayBecomesDebtWhenShort(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+	g.transferCoins(player.ID, AccountPrizePool, player.Coins-50, "test")
+	stackDeck(g, DeckChance, "chance-fine")
+
+	if err := g.drawCard(player, DeckChance); err != nil {
+		t.Fatalf("draw card: %v", err)
+	}
+	if player.Coins != 50 || player.DebtTotal() != 100 || player.Debts[0].CreditorID != AccountPrizePool {
+		t.Fatalf("got coins %d and debts %+v, want a 100 debt to the prize pool", player.Coins, player.Debts)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestUsePrisonCardReturnsCardOnReshuffle(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+	holdCard(g, player, DeckChance, "chance-release")
+	if err := g.sendToPrison(player); err != nil {
+		t.Fatalf("send to prison: %v", err)
+	}
+
+	if _, err := g.UsePrisonCard(player.ID); err != nil {
+		t.Fatalf("use prison card: %v", err)
+	}
+	if player.InPrison || len(player.HeldCards) != 0 {
+		t.Fatalf("got in prison %v and held cards %v, want released without cards", player.InPrison, player.HeldCards)
+	}
+
+	// 使用过的卡片在下次洗牌时回到牌堆
+	g.shuffleDeck(DeckChance)
+	if pile := g.DrawPiles[DeckChance]; !slices.Contains(pile, "chance-release") {
+		t.Fatalf("got draw pile %v, want chance-release back in the deck", pile)
+	}
+	assertReplayMatches(t, g)
+}
@@ -0,0 +1,19 @@
+// internal/game/clock.go
+package game
+
+import "time"
+
+// Clock 时钟接口，便于在测试中注入可控的时间
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock 使用系统时间的时钟
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock 默认的系统时钟
+var SystemClock Clock = systemClock{}
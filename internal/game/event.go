@@ -0,0 +1,821 @@
+// internal/game/event.go
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventType 事件类型
+type EventType string
+
+const (
+	EventGameCreated          EventType = "gameCreated"
+	EventPlayerJoined         EventType = "playerJoined"
+	EventPlayerLeft           EventType = "playerLeft"
+	EventSettingsChanged      EventType = "settingsChanged"
+	EventTurnOrderDecided     EventType = "turnOrderDecided"
+	EventGameStarted          EventType = "gameStarted"
+	EventDiceRolled           EventType = "diceRolled"
+	EventExtraRollGranted     EventType = "extraRollGranted"
+	EventPlayerMoved          EventType = "playerMoved"
+	EventCoinsTransferred     EventType = "coinsTransferred"
+	EventPropertyOwnerChanged EventType = "propertyOwnerChanged"
+	EventPropertyLevelChanged EventType = "propertyLevelChanged"
+	EventPropertyMortgaged    EventType = "propertyMortgageChanged"
+	EventDeckShuffled         EventType = "deckShuffled"
+	EventCardDrawn            EventType = "cardDrawn"
+	EventCardHeld             EventType = "cardHeld"
+	EventPrisonEntered        EventType = "prisonEntered"
+	EventPrisonTurnServed     EventType = "prisonTurnServed"
+	EventPrisonReleased       EventType = "prisonReleased"
+	EventEscapeAttempted      EventType = "escapeAttempted"
+	EventCardUsed             EventType = "cardUsed"
+	EventCardReturned         EventType = "cardReturned"
+	EventDebtIncurred         EventType = "debtIncurred"
+	EventDebtSettled          EventType = "debtSettled"
+	EventPlayerBankrupt       EventType = "playerBankrupt"
+	EventTradeProposed        EventType = "tradeProposed"
+	EventTradeStatusChanged   EventType = "tradeStatusChanged"
+	EventAuctionStarted       EventType = "auctionStarted"
+	EventAuctionBidPlaced     EventType = "auctionBidPlaced"
+	EventAuctionClosed        EventType = "auctionClosed"
+	EventBankVisited          EventType = "bankVisited"
+	EventLoanTaken            EventType = "loanTaken"
+	EventLoanRepaid           EventType = "loanRepaid"
+	EventLoanDefaulted        EventType = "loanDefaulted"
+	EventTurnChanged          EventType = "turnChanged"
+	EventGameEnded            EventType = "gameEnded"
+	EventGameAbandoned        EventType = "gameAbandoned"
+	EventGamePaused           EventType = "gamePaused"
+	EventGameResumed          EventType = "gameResumed"
+	EventActionRecorded       EventType = "actionRecorded"
+)
+
+// AccountPrizePool 资金转移中代表奖池的账户标识
+const AccountPrizePool = "@prizePool"
+
+// Event 表示一次游戏状态变化
+// 游戏的所有状态修改都通过事件完成，按顺序重放事件即可重建游戏状态
+type Event struct {
+	Index       int          `json:"index"`
+	Type        EventType    `json:"type"`
+	Timestamp   time.Time    `json:"timestamp"`
+	RandomDraws uint64       `json:"randomDraws"` // 应用该事件后随机数源已生成的随机数个数
+	Payload     EventPayload `json:"payload"`
+}
+
+// EventPayload 事件数据
+type EventPayload interface {
+	EventType() EventType
+	apply(g *Game, e *Event)
+}
+
+// eventPayloadFactories 用于反序列化事件数据
+var eventPayloadFactories = map[EventType]func() EventPayload{
+	EventGameCreated:          func() EventPayload { return &GameCreated{} },
+	EventPlayerJoined:         func() EventPayload { return &PlayerJoined{} },
+	EventPlayerLeft:           func() EventPayload { return &PlayerLeft{} },
+	EventSettingsChanged:      func() EventPayload { return &SettingsChanged{} },
+	EventTurnOrderDecided:     func() EventPayload { return &TurnOrderDecided{} },
+	EventGameStarted:          func() EventPayload { return &GameStarted{} },
+	EventDiceRolled:           func() EventPayload { return &DiceRolled{} },
+	EventExtraRollGranted:     func() EventPayload { return &ExtraRollGranted{} },
+	EventPlayerMoved:          func() EventPayload { return &PlayerMoved{} },
+	EventCoinsTransferred:     func() EventPayload { return &CoinsTransferred{} },
+	EventPropertyOwnerChanged: func() EventPayload { return &PropertyOwnerChanged{} },
+	EventPropertyLevelChanged: func() EventPayload { return &PropertyLevelChanged{} },
+	EventPropertyMortgaged:    func() EventPayload { return &PropertyMortgageChanged{} },
+	EventDeckShuffled:         func() EventPayload { return &DeckShuffled{} },
+	EventCardDrawn:            func() EventPayload { return &CardDrawn{} },
+	EventCardHeld:             func() EventPayload { return &CardHeld{} },
+	EventPrisonEntered:        func() EventPayload { return &PrisonEntered{} },
+	EventPrisonTurnServed:     func() EventPayload { return &PrisonTurnServed{} },
+	EventPrisonReleased:       func() EventPayload { return &PrisonReleased{} },
+	EventEscapeAttempted:      func() EventPayload { return &EscapeAttempted{} },
+	EventCardUsed:             func() EventPayload { return &CardUsed{} },
+	EventCardReturned:         func() EventPayload { return &CardReturned{} },
+	EventDebtIncurred:         func() EventPayload { return &DebtIncurred{} },
+	EventDebtSettled:          func() EventPayload { return &DebtSettled{} },
+	EventPlayerBankrupt:       func() EventPayload { return &PlayerBankrupt{} },
+	EventTradeProposed:        func() EventPayload { return &TradeProposed{} },
+	EventTradeStatusChanged:   func() EventPayload { return &TradeStatusChanged{} },
+	EventAuctionStarted:       func() EventPayload { return &AuctionStarted{} },
+	EventAuctionBidPlaced:     func() EventPayload { return &AuctionBidPlaced{} },
+	EventAuctionClosed:        func() EventPayload { return &AuctionClosed{} },
+	EventBankVisited:          func() EventPayload { return &BankVisited{} },
+	EventLoanTaken:            func() EventPayload { return &LoanTaken{} },
+	EventLoanRepaid:           func() EventPayload { return &LoanRepaid{} },
+	EventLoanDefaulted:        func() EventPayload { return &LoanDefaulted{} },
+	EventTurnChanged:          func() EventPayload { return &TurnChanged{} },
+	EventGameEnded:            func() EventPayload { return &GameEnded{} },
+	EventGameAbandoned:        func() EventPayload { return &GameAbandoned{} },
+	EventGamePaused:           func() EventPayload { return &GamePaused{} },
+	EventGameResumed:          func() EventPayload { return &GameResumed{} },
+	EventActionRecorded:       func() EventPayload { return &ActionRecorded{} },
+}
+
+// UnmarshalJSON 根据事件类型解析事件数据
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Index       int             `json:"index"`
+		Type        EventType       `json:"type"`
+		Timestamp   time.Time       `json:"timestamp"`
+		RandomDraws uint64          `json:"randomDraws"`
+		Payload     json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	factory, exists := eventPayloadFactories[raw.Type]
+	if !exists {
+		return fmt.Errorf("unknown event type %q", raw.Type)
+	}
+	payload := factory()
+	if err := json.Unmarshal(raw.Payload, payload); err != nil {
+		return err
+	}
+
+	e.Index = raw.Index
+	e.Type = raw.Type
+	e.Timestamp = raw.Timestamp
+	e.RandomDraws = raw.RandomDraws
+	e.Payload = payload
+	return nil
+}
+
+// emit 生成事件、应用到游戏状态并追加到事件日志
+// 调用方需持有游戏锁
+func (g *Game) emit(payload EventPayload) *Event {
+	event := &Event{
+		Index:     len(g.Events),
+		Type:      payload.EventType(),
+		Timestamp: g.now(),
+		Payload:   payload,
+	}
+	payload.apply(g, event)
+	event.RandomDraws = g.rng.Draws()
+	g.appendEvent(event)
+	for _, observer := range g.observers {
+		observer(g, event)
+	}
+	return event
+}
+
+// appendEvent 将已应用的事件追加到日志，递增游戏版本并唤醒等待变化的调用方
+func (g *Game) appendEvent(event *Event) {
+	g.Events = append(g.Events, event)
+	g.Version++
+	if g.changed != nil {
+		close(g.changed)
+		g.changed = nil
+	}
+}
+
+// transferCoins 在玩家与奖池之间转移金币
+func (g *Game) transferCoins(from, to string, amount int, reason string) {
+	if amount == 0 {
+		return
+	}
+	g.emit(&CoinsTransferred{From: from, To: to, Amount: amount, Reason: reason})
+}
+
+// GameCreated 游戏创建事件
+type GameCreated struct {
+	GameID   string       `json:"gameId"`
+	HostID   string       `json:"hostId,omitempty"`
+	Seed     int64        `json:"seed"`
+	Settings GameSettings `json:"settings"`
+	Map      *GameMap     `json:"map"`
+}
+
+func (p *GameCreated) EventType() EventType { return EventGameCreated }
+
+func (p *GameCreated) apply(g *Game, e *Event) {
+	g.ID = p.GameID
+	g.HostID = p.HostID
+	g.Seed = p.Seed
+	g.Settings = p.Settings
+	g.rng = NewRandom(p.Seed)
+	g.Map = p.Map.Clone()
+	g.Status = StatusWaiting
+	g.CreatedAt = e.Timestamp
+}
+
+// PlayerJoined 玩家加入事件
+type PlayerJoined struct {
+	PlayerID string `json:"playerId"`
+	Name     string `json:"name"`
+	Coins    int    `json:"coins"`
+}
+
+func (p *PlayerJoined) EventType() EventType { return EventPlayerJoined }
+
+func (p *PlayerJoined) apply(g *Game, e *Event) {
+	player := NewPlayer(p.PlayerID, p.Name, p.Coins)
+	player.JoinTime = e.Timestamp
+	g.Players[p.PlayerID] = player
+	g.TurnOrder = append(g.TurnOrder, p.PlayerID)
+}
+
+// PlayerLeft 玩家离开事件
+type PlayerLeft struct {
+	PlayerID string `json:"playerId"`
+}
+
+func (p *PlayerLeft) EventType() EventType { return EventPlayerLeft }
+
+func (p *PlayerLeft) apply(g *Game, e *Event) {
+	delete(g.Players, p.PlayerID)
+	for i, id := range g.TurnOrder {
+		if id == p.PlayerID {
+			g.TurnOrder = append(g.TurnOrder[:i:i], g.TurnOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// SettingsChanged 规则设置修改事件，只在等待开始时产生
+type SettingsChanged struct {
+	Settings GameSettings `json:"settings"`
+}
+
+func (p *SettingsChanged) EventType() EventType { return EventSettingsChanged }
+
+func (p *SettingsChanged) apply(g *Game, e *Event) {
+	g.Settings = p.Settings
+}
+
+// TurnOrderDecided 座位顺序确定事件，Rolls 记录开局掷骰的点数
+type TurnOrderDecided struct {
+	Order []string         `json:"order"`
+	Rolls map[string][]int `json:"rolls,omitempty"`
+}
+
+func (p *TurnOrderDecided) EventType() EventType { return EventTurnOrderDecided }
+
+func (p *TurnOrderDecided) apply(g *Game, e *Event) {
+	g.TurnOrder = make([]string, len(p.Order))
+	copy(g.TurnOrder, p.Order)
+}
+
+// GameStarted 游戏开始事件
+type GameStarted struct {
+	FirstPlayerID string `json:"firstPlayerId"`
+}
+
+func (p *GameStarted) EventType() EventType { return EventGameStarted }
+
+func (p *GameStarted) apply(g *Game, e *Event) {
+	g.Status = StatusPlaying
+	g.CurrentPlayerID = p.FirstPlayerID
+	g.StartTime = e.Timestamp
+	g.CurrentTurnStarted = e.Timestamp
+	for _, player := range g.Players {
+		player.Status = PlayerStatusPlaying
+	}
+	g.Players[p.FirstPlayerID].TurnsTaken++
+}
+
+// DiceRolled 掷骰子移动事件，Doubles 表示计入连续对子次数
+type DiceRolled struct {
+	PlayerID string `json:"playerId"`
+	Dice     []int  `json:"dice"`
+	Doubles  bool   `json:"doubles,omitempty"`
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+}
+
+func (p *DiceRolled) EventType() EventType { return EventDiceRolled }
+
+func (p *DiceRolled) apply(g *Game, e *Event) {
+	player := g.Players[p.PlayerID]
+	player.Position = p.To
+	player.HasRolled = true
+	if p.Doubles {
+		player.DoublesRolled++
+	}
+}
+
+// ExtraRollGranted 掷出对子后获得额外掷骰机会的事件
+type ExtraRollGranted struct {
+	PlayerID string `json:"playerId"`
+}
+
+func (p *ExtraRollGranted) EventType() EventType { return EventExtraRollGranted }
+
+func (p *ExtraRollGranted) apply(g *Game, e *Event) {
+	g.Players[p.PlayerID].HasRolled = false
+}
+
+// PlayerMoved 玩家被卡片等效果直接移动的事件
+type PlayerMoved struct {
+	PlayerID string `json:"playerId"`
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+}
+
+func (p *PlayerMoved) EventType() EventType { return EventPlayerMoved }
+
+func (p *PlayerMoved) apply(g *Game, e *Event) {
+	g.Players[p.PlayerID].Position = p.To
+}
+
+// CoinsTransferred 金币转移事件，From/To 为玩家ID、AccountPrizePool 或玩家的存款账户
+type CoinsTransferred struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int    `json:"amount"`
+	Reason string `json:"reason"`
+}
+
+func (p *CoinsTransferred) EventType() EventType { return EventCoinsTransferred }
+
+func (p *CoinsTransferred) apply(g *Game, e *Event) {
+	g.adjustAccount(p.From, -p.Amount)
+	g.adjustAccount(p.To, p.Amount)
+}
+
+// adjustAccount 调整账户余额
+func (g *Game) adjustAccount(account string, delta int) {
+	if account == AccountPrizePool {
+		g.PrizePool += delta
+		return
+	}
+	if playerID, ok := DepositOwner(account); ok {
+		if player := g.Players[playerID]; player != nil {
+			player.Deposit += delta
+		}
+		return
+	}
+	if player := g.Players[account]; player != nil {
+		player.Coins += delta
+	}
+}
+
+// PropertyOwnerChanged 地产所有权变更事件，OwnerID 为空表示归还银行
+type PropertyOwnerChanged struct {
+	Position int    `json:"position"`
+	OwnerID  string `json:"ownerId"`
+}
+
+func (p *PropertyOwnerChanged) EventType() EventType { return EventPropertyOwnerChanged }
+
+func (p *PropertyOwnerChanged) apply(g *Game, e *Event) {
+	g.Map.Tiles[p.Position].OwnerID = p.OwnerID
+}
+
+// PropertyLevelChanged 地产等级变更事件
+type PropertyLevelChanged struct {
+	Position int `json:"position"`
+	Level    int `json:"level"`
+}
+
+func (p *PropertyLevelChanged) EventType() EventType { return EventPropertyLevelChanged }
+
+func (p *PropertyLevelChanged) apply(g *Game, e *Event) {
+	g.Map.Tiles[p.Position].Level = p.Level
+}
+
+// PropertyMortgageChanged 地产抵押状态变更事件
+type PropertyMortgageChanged struct {
+	Position  int  `json:"position"`
+	Mortgaged bool `json:"mortgaged"`
+}
+
+func (p *PropertyMortgageChanged) EventType() EventType { return EventPropertyMortgaged }
+
+func (p *PropertyMortgageChanged) apply(g *Game, e *Event) {
+	g.Map.Tiles[p.Position].Mortgaged = p.Mortgaged
+}
+
+// DeckShuffled 洗牌事件，Order 为洗牌后的抽牌顺序
+type DeckShuffled struct {
+	Deck  string   `json:"deck"`
+	Order []string `json:"order"`
+}
+
+func (p *DeckShuffled) EventType() EventType { return EventDeckShuffled }
+
+func (p *DeckShuffled) apply(g *Game, e *Event) {
+	g.DrawPiles[p.Deck] = append([]string(nil), p.Order...)
+}
+
+// CardDrawn 抽卡事件，卡片从牌堆顶移除，具体效果由随后的事件体现
+type CardDrawn struct {
+	PlayerID string     `json:"playerId"`
+	Deck     string     `json:"deck"`
+	CardID   string     `json:"cardId"`
+	Effect   CardEffect `json:"effect"`
+}
+
+func (p *CardDrawn) EventType() EventType { return EventCardDrawn }
+
+func (p *CardDrawn) apply(g *Game, e *Event) {
+	pile := g.DrawPiles[p.Deck]
+	for i, id := range pile {
+		if id == p.CardID {
+			g.DrawPiles[p.Deck] = append(pile[:i:i], pile[i+1:]...)
+			return
+		}
+	}
+}
+
+// CardHeld 玩家保留卡片的事件，保留的卡片在使用前不会被洗回牌堆
+type CardHeld struct {
+	PlayerID string `json:"playerId"`
+	Deck     string `json:"deck"`
+	CardID   string `json:"cardId"`
+}
+
+func (p *CardHeld) EventType() EventType { return EventCardHeld }
+
+func (p *CardHeld) apply(g *Game, e *Event) {
+	player := g.Players[p.PlayerID]
+	player.HeldCards = append(player.HeldCards, HeldCard{Deck: p.Deck, CardID: p.CardID})
+}
+
+// PrisonEntered 入狱事件
+type PrisonEntered struct {
+	PlayerID string `json:"playerId"`
+	Days     int    `json:"days"`
+}
+
+func (p *PrisonEntered) EventType() EventType { return EventPrisonEntered }
+
+func (p *PrisonEntered) apply(g *Game, e *Event) {
+	player := g.Players[p.PlayerID]
+	player.InPrison = true
+	player.PrisonDays = p.Days
+	player.EscapeAttempts = 0
+}
+
+// PrisonTurnServed 在监狱中度过一个回合的事件
+type PrisonTurnServed struct {
+	PlayerID string `json:"playerId"`
+}
+
+func (p *PrisonTurnServed) EventType() EventType { return EventPrisonTurnServed }
+
+func (p *PrisonTurnServed) apply(g *Game, e *Event) {
+	player := g.Players[p.PlayerID]
+	player.PrisonDays--
+	player.HasRolled = true
+}
+
+// PrisonReleased 出狱事件，Reason 为 served、doubles、bail 或 card
+type PrisonReleased struct {
+	PlayerID string `json:"playerId"`
+	Reason   string `json:"reason"`
+}
+
+func (p *PrisonReleased) EventType() EventType { return EventPrisonReleased }
+
+func (p *PrisonReleased) apply(g *Game, e *Event) {
+	g.Players[p.PlayerID].ExitPrison()
+}
+
+// EscapeAttempted 在狱中掷骰尝试越狱的事件
+type EscapeAttempted struct {
+	PlayerID string `json:"playerId"`
+	Dice     []int  `json:"dice"`
+}
+
+func (p *EscapeAttempted) EventType() EventType { return EventEscapeAttempted }
+
+func (p *EscapeAttempted) apply(g *Game, e *Event) {
+	g.Players[p.PlayerID].EscapeAttempts++
+}
+
+// CardUsed 玩家使用持有卡片的事件，卡片在下次洗牌时回到牌堆
+type CardUsed struct {
+	PlayerID string `json:"playerId"`
+	Deck     string `json:"deck"`
+	CardID   string `json:"cardId"`
+}
+
+func (p *CardUsed) EventType() EventType { return EventCardUsed }
+
+func (p *CardUsed) apply(g *Game, e *Event) {
+	g.Players[p.PlayerID].removeHeldCard(p.Deck, p.CardID)
+}
+
+// CardReturned 破产玩家交出持有卡片的事件
+// ToPlayerID 非空时卡片转给该玩家，否则放回所属牌堆的底部
+type CardReturned struct {
+	PlayerID   string `json:"playerId"`
+	Deck       string `json:"deck"`
+	CardID     string `json:"cardId"`
+	ToPlayerID string `json:"toPlayerId,omitempty"`
+}
+
+func (p *CardReturned) EventType() EventType { return EventCardReturned }
+
+func (p *CardReturned) apply(g *Game, e *Event) {
+	g.Players[p.PlayerID].removeHeldCard(p.Deck, p.CardID)
+
+	if p.ToPlayerID != "" {
+		receiver := g.Players[p.ToPlayerID]
+		receiver.HeldCards = append(receiver.HeldCards, HeldCard{Deck: p.Deck, CardID: p.CardID})
+		return
+	}
+	pile := g.DrawPiles[p.Deck]
+	g.DrawPiles[p.Deck] = append(pile[:len(pile):len(pile)], p.CardID)
+}
+
+// DebtIncurred 玩家产生债务事件，已有债务时追加一笔，每笔债务保留各自的债权人
+type DebtIncurred struct {
+	PlayerID   string `json:"playerId"`
+	CreditorID string `json:"creditorId"`
+	Amount     int    `json:"amount"`
+	Reason     string `json:"reason"`
+}
+
+func (p *DebtIncurred) EventType() EventType { return EventDebtIncurred }
+
+func (p *DebtIncurred) apply(g *Game, e *Event) {
+	player := g.Players[p.PlayerID]
+	player.Debts = append(player.Debts, Debt{
+		CreditorID: p.CreditorID,
+		Amount:     p.Amount,
+		Reason:     p.Reason,
+	})
+}
+
+// DebtSettled 所有债务还清事件
+type DebtSettled struct {
+	PlayerID string `json:"playerId"`
+}
+
+func (p *DebtSettled) EventType() EventType { return EventDebtSettled }
+
+func (p *DebtSettled) apply(g *Game, e *Event) {
+	g.Players[p.PlayerID].Debts = nil
+}
+
+// PlayerBankrupt 玩家破产出局事件
+type PlayerBankrupt struct {
+	PlayerID   string `json:"playerId"`
+	CreditorID string `json:"creditorId"`
+}
+
+func (p *PlayerBankrupt) EventType() EventType { return EventPlayerBankrupt }
+
+func (p *PlayerBankrupt) apply(g *Game, e *Event) {
+	player := g.Players[p.PlayerID]
+	player.Status = PlayerStatusBankrupt
+	player.Debts = nil
+	player.Loan = nil
+	player.HasRolled = false
+	player.AtBank = false
+}
+
+// TradeProposed 交易报价事件
+type TradeProposed struct {
+	Offer *TradeOffer `json:"offer"`
+}
+
+func (p *TradeProposed) EventType() EventType { return EventTradeProposed }
+
+func (p *TradeProposed) apply(g *Game, e *Event) {
+	g.Trades = append(g.Trades, p.Offer.Clone())
+}
+
+// TradeStatusChanged 交易状态变更事件
+type TradeStatusChanged struct {
+	TradeID string      `json:"tradeId"`
+	Status  TradeStatus `json:"status"`
+}
+
+func (p *TradeStatusChanged) EventType() EventType { return EventTradeStatusChanged }
+
+func (p *TradeStatusChanged) apply(g *Game, e *Event) {
+	for _, offer := range g.Trades {
+		if offer.ID == p.TradeID {
+			offer.Status = p.Status
+			return
+		}
+	}
+}
+
+// AuctionStarted 拍卖开始事件
+type AuctionStarted struct {
+	AuctionID string    `json:"auctionId"`
+	Position  int       `json:"position"`
+	EndsAt    time.Time `json:"endsAt"`
+}
+
+func (p *AuctionStarted) EventType() EventType { return EventAuctionStarted }
+
+func (p *AuctionStarted) apply(g *Game, e *Event) {
+	g.Auctions = append(g.Auctions, &Auction{
+		ID:        p.AuctionID,
+		Position:  p.Position,
+		Status:    AuctionOpen,
+		StartedAt: e.Timestamp,
+		EndsAt:    p.EndsAt,
+		Bids:      make([]AuctionBid, 0),
+	})
+}
+
+// AuctionBidPlaced 拍卖出价事件
+type AuctionBidPlaced struct {
+	AuctionID string `json:"auctionId"`
+	PlayerID  string `json:"playerId"`
+	Amount    int    `json:"amount"`
+}
+
+func (p *AuctionBidPlaced) EventType() EventType { return EventAuctionBidPlaced }
+
+func (p *AuctionBidPlaced) apply(g *Game, e *Event) {
+	if auction := g.findAuction(p.AuctionID); auction != nil {
+		auction.Bids = append(auction.Bids, AuctionBid{
+			PlayerID:  p.PlayerID,
+			Amount:    p.Amount,
+			Timestamp: e.Timestamp,
+		})
+	}
+}
+
+// AuctionClosed 拍卖结束事件，WinnerID 为空表示流拍
+type AuctionClosed struct {
+	AuctionID string `json:"auctionId"`
+	WinnerID  string `json:"winnerId,omitempty"`
+	Amount    int    `json:"amount,omitempty"`
+}
+
+func (p *AuctionClosed) EventType() EventType { return EventAuctionClosed }
+
+func (p *AuctionClosed) apply(g *Game, e *Event) {
+	auction := g.findAuction(p.AuctionID)
+	if auction == nil {
+		return
+	}
+	auction.Status = AuctionUnsold
+	if p.WinnerID != "" {
+		auction.Status = AuctionSold
+		auction.WinnerID = p.WinnerID
+		auction.WinningBid = p.Amount
+	}
+}
+
+// findAuction 根据ID查找拍卖
+func (g *Game) findAuction(id string) *Auction {
+	for _, auction := range g.Auctions {
+		if auction.ID == id {
+			return auction
+		}
+	}
+	return nil
+}
+
+// BankVisited 玩家停留或经过银行的事件，本回合内可办理银行业务
+type BankVisited struct {
+	PlayerID string `json:"playerId"`
+}
+
+func (p *BankVisited) EventType() EventType { return EventBankVisited }
+
+func (p *BankVisited) apply(g *Game, e *Event) {
+	g.Players[p.PlayerID].AtBank = true
+}
+
+// LoanTaken 贷款发放事件，本金转移由随后的金币转移事件体现
+type LoanTaken struct {
+	PlayerID  string `json:"playerId"`
+	Principal int    `json:"principal"`
+	Balance   int    `json:"balance"`
+	DueTurn   int    `json:"dueTurn"`
+}
+
+func (p *LoanTaken) EventType() EventType { return EventLoanTaken }
+
+func (p *LoanTaken) apply(g *Game, e *Event) {
+	g.Players[p.PlayerID].Loan = &Loan{
+		Principal: p.Principal,
+		Balance:   p.Balance,
+		DueTurn:   p.DueTurn,
+	}
+}
+
+// LoanRepaid 偿还贷款事件，本息还清后贷款关闭
+type LoanRepaid struct {
+	PlayerID string `json:"playerId"`
+	Amount   int    `json:"amount"`
+}
+
+func (p *LoanRepaid) EventType() EventType { return EventLoanRepaid }
+
+func (p *LoanRepaid) apply(g *Game, e *Event) {
+	player := g.Players[p.PlayerID]
+	player.Loan.Balance -= p.Amount
+	if player.Loan.Balance <= 0 {
+		player.Loan = nil
+	}
+}
+
+// LoanDefaulted 贷款到期未还事件，剩余本息转为欠奖池的债务
+type LoanDefaulted struct {
+	PlayerID string `json:"playerId"`
+}
+
+func (p *LoanDefaulted) EventType() EventType { return EventLoanDefaulted }
+
+func (p *LoanDefaulted) apply(g *Game, e *Event) {
+	player := g.Players[p.PlayerID]
+	player.Debts = append(player.Debts, Debt{
+		CreditorID: AccountPrizePool,
+		Amount:     player.Loan.Balance,
+		Reason:     "loan",
+	})
+	player.Loan = nil
+}
+
+// TurnChanged 回合切换事件
+type TurnChanged struct {
+	PreviousPlayerID string `json:"previousPlayerId"`
+	PlayerID         string `json:"playerId"`
+}
+
+func (p *TurnChanged) EventType() EventType { return EventTurnChanged }
+
+func (p *TurnChanged) apply(g *Game, e *Event) {
+	if previous := g.Players[p.PreviousPlayerID]; previous != nil {
+		previous.HasRolled = false
+		previous.AtBank = false
+		previous.DoublesRolled = 0
+	}
+	if next := g.Players[p.PlayerID]; next != nil {
+		next.TurnsTaken++
+	}
+	g.CurrentPlayerID = p.PlayerID
+	g.CurrentTurnStarted = e.Timestamp
+}
+
+// GameEnded 游戏结束事件，Ranking 为最终排名
+type GameEnded struct {
+	Ranking []string `json:"ranking"`
+}
+
+func (p *GameEnded) EventType() EventType { return EventGameEnded }
+
+func (p *GameEnded) apply(g *Game, e *Event) {
+	g.Status = StatusFinished
+	g.Ranking = append([]string(nil), p.Ranking...)
+}
+
+// GameAbandoned 游戏放弃事件，等待开始超时的游戏不再进行
+type GameAbandoned struct{}
+
+func (p *GameAbandoned) EventType() EventType { return EventGameAbandoned }
+
+func (p *GameAbandoned) apply(g *Game, e *Event) {
+	g.Status = StatusAbandoned
+}
+
+// GamePaused 游戏暂停事件，暂停期间回合、游戏和拍卖计时停止
+type GamePaused struct {
+	Reason PauseReason `json:"reason,omitempty"` // 为空表示停机暂停
+}
+
+func (p *GamePaused) EventType() EventType { return EventGamePaused }
+
+func (p *GamePaused) apply(g *Game, e *Event) {
+	g.Status = StatusPaused
+	g.PausedAt = e.Timestamp
+	g.PauseReason = p.Reason
+	if g.PauseReason == "" {
+		g.PauseReason = PauseShutdown
+	}
+}
+
+// GameResumed 游戏恢复事件，回合、游戏和拍卖的计时顺延暂停的时长
+type GameResumed struct{}
+
+func (p *GameResumed) EventType() EventType { return EventGameResumed }
+
+func (p *GameResumed) apply(g *Game, e *Event) {
+	paused := e.Timestamp.Sub(g.PausedAt)
+	g.StartTime = g.StartTime.Add(paused)
+	g.CurrentTurnStarted = g.CurrentTurnStarted.Add(paused)
+	if auction := g.currentAuction(); auction != nil {
+		auction.EndsAt = auction.EndsAt.Add(paused)
+	}
+	g.Status = StatusPlaying
+	g.PausedAt = time.Time{}
+	g.PauseReason = ""
+}
+
+// ActionRecorded 动作记录事件，用于在重放时恢复动作日志
+type ActionRecorded struct {
+	Action *GameAction `json:"action"`
+}
+
+func (p *ActionRecorded) EventType() EventType { return EventActionRecorded }
+
+func (p *ActionRecorded) apply(g *Game, e *Event) {
+	action := *p.Action
+	g.Actions = append(g.Actions, &action)
+}
@@ -3,44 +3,130 @@ package game
 
 import (
 	"monopoly/pkg/utils"
-	"sort"
 	"sync"
 	"time"
 )
 
 // Game 表示一局游戏
 type Game struct {
-	ID                 string             `json:"id"`
-	Players            map[string]*Player `json:"players"`
-	Status             GameStatus         `json:"status"`
-	PrizePool          int                `json:"prizePool"`
-	Map                *GameMap           `json:"map"`
-	CurrentPlayerID    string             `json:"currentPlayerId"`
-	CurrentTurnStarted time.Time          `json:"currentTurnStarted"`
-	StartTime          time.Time          `json:"startTime"`
-	Actions            []*GameAction      `json:"actions"`
+	ID                 string              `json:"id"`
+	HostID             string              `json:"hostId,omitempty"` // 创建游戏的用户，可以管理自己的游戏
+	Seed               int64               `json:"seed"`
+	Settings           GameSettings        `json:"settings"`
+	Players            map[string]*Player  `json:"players"`
+	TurnOrder          []string            `json:"turnOrder"`         // 座位顺序，开始前为加入顺序
+	Ranking            []string            `json:"ranking,omitempty"` // 游戏结束时的最终排名
+	Status             GameStatus          `json:"status"`
+	PrizePool          int                 `json:"prizePool"`
+	Map                *GameMap            `json:"map"`
+	CurrentPlayerID    string              `json:"currentPlayerId"`
+	CurrentTurnStarted time.Time           `json:"currentTurnStarted"`
+	StartTime          time.Time           `json:"startTime"`
+	Actions            []*GameAction       `json:"actions"`
+	Trades             []*TradeOffer       `json:"trades"`
+	Auctions           []*Auction          `json:"auctions"`
+	DrawPiles          map[string][]string `json:"drawPiles"` // 各牌堆剩余卡片的抽牌顺序
+	CreatedAt          time.Time           `json:"createdAt"`
+	PausedAt           time.Time           `json:"pausedAt,omitempty"`
+	PauseReason        PauseReason         `json:"pauseReason,omitempty"`
+	Version            int                 `json:"version"` // 状态版本，每应用一个事件加1，单调递增
+	Events             []*Event            `json:"-"`
+	observers          []Observer
+	changed            chan struct{} // 版本变化时关闭，由 Changed 按需创建
+	rng                *Random
+	clock              Clock
 	mutex              sync.RWMutex
 }
 
+// Observer 游戏事件观察者，每个事件应用后同步调用
+// 调用时持有游戏锁，观察者只能读取游戏状态，不得调用游戏的加锁方法
+type Observer func(g *Game, e *Event)
+
 // PlayerResult 表示玩家的最终游戏结果
 type PlayerResult struct {
+	Rank          int    `json:"rank"`
 	PlayerID      string `json:"playerId"`
 	Name          string `json:"name"`
 	FinalCoins    int    `json:"finalCoins"`
 	PropertyValue int    `json:"propertyValue"`
 	PropertyCount int    `json:"propertyCount"`
+	LoanBalance   int    `json:"loanBalance"`
 	TotalAssets   int    `json:"totalAssets"`
+	Bankrupt      bool   `json:"bankrupt"`
+}
+
+// NewGame 使用指定的随机种子、规则设置、地图和时钟创建新游戏，hostID 为创建游戏的用户
+func NewGame(id, hostID string, seed int64, settings GameSettings, gameMap *GameMap, clock Clock) *Game {
+	g := newEmptyGame()
+	g.clock = clock
+	g.emit(&GameCreated{
+		GameID:   id,
+		HostID:   hostID,
+		Seed:     seed,
+		Settings: settings,
+		Map:      gameMap,
+	})
+	return g
 }
 
-// NewGame 创建新游戏
-func NewGame(id string) *Game {
+// newEmptyGame 创建尚未应用任何事件的游戏
+func newEmptyGame() *Game {
 	return &Game{
-		ID:      id,
-		Players: make(map[string]*Player),
-		Status:  StatusWaiting,
-		Map:     NewDefaultMap(),
-		Actions: make([]*GameAction, 0),
+		Players:   make(map[string]*Player),
+		TurnOrder: make([]string, 0),
+		Actions:   make([]*GameAction, 0),
+		Trades:    make([]*TradeOffer, 0),
+		Auctions:  make([]*Auction, 0),
+		DrawPiles: make(map[string][]string),
+		Events:    make([]*Event, 0),
+		clock:     SystemClock,
+	}
+}
+
+// Observe 注册事件观察者，只接收注册之后产生的事件
+func (g *Game) Observe(observer Observer) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.observers = append(g.observers, observer)
+}
+
+// View 持有游戏读锁调用 fn，fn 执行期间不会产生新事件
+// fn 只能读取游戏状态，不得调用游戏的加锁方法
+func (g *Game) View(fn func(g *Game)) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	fn(g)
+}
+
+// Changed 返回在游戏版本超过 version 时关闭的通道，用于等待游戏状态变化
+func (g *Game) Changed(version int) <-chan struct{} {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.Version > version {
+		closed := make(chan struct{})
+		close(closed)
+		return closed
+	}
+	if g.changed == nil {
+		g.changed = make(chan struct{})
+	}
+	return g.changed
+}
+
+// now 获取游戏时钟的当前时间
+func (g *Game) now() time.Time {
+	return g.clock.Now()
+}
+
+// timerNow 获取计时使用的当前时间，暂停的游戏停在暂停时刻
+func (g *Game) timerNow() time.Time {
+	if g.Status == StatusPaused {
+		return g.PausedAt
 	}
+	return g.now()
 }
 
 // AddPlayer 添加玩家到游戏
@@ -48,7 +134,7 @@ func (g *Game) AddPlayer(player *Player) error {
 	g.mutex.Lock()
 	defer g.mutex.Unlock()
 
-	if len(g.Players) >= MaxPlayers {
+	if len(g.Players) >= g.Settings.MaxPlayers {
 		return utils.ErrGameFull
 	}
 
@@ -60,10 +146,94 @@ func (g *Game) AddPlayer(player *Player) error {
 		return utils.ErrPlayerExists
 	}
 
-	g.Players[player.ID] = player
+	g.emit(&PlayerJoined{
+		PlayerID: player.ID,
+		Name:     player.Name,
+		Coins:    player.Coins,
+	})
+	return nil
+}
+
+// RemovePlayer 将玩家移出游戏，仅在等待状态下允许
+func (g *Game) RemovePlayer(playerID string) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.Status != StatusWaiting {
+		return utils.ErrGameInProgress
+	}
+
+	if _, exists := g.Players[playerID]; !exists {
+		return utils.ErrPlayerNotFound
+	}
+
+	g.emit(&PlayerLeft{PlayerID: playerID})
+	return nil
+}
+
+// UpdateSettings 修改规则设置，仅在等待状态下允许
+// 已有玩家加入时不能修改买入金额，玩家上限不能低于已加入的人数
+func (g *Game) UpdateSettings(settings GameSettings) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.Status != StatusWaiting {
+		return utils.ErrGameInProgress
+	}
+
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	if len(g.Players) > 0 && settings.BuyIn != g.Settings.BuyIn {
+		return invalidSetting("buyIn")
+	}
+	if settings.MaxPlayers < len(g.Players) {
+		return invalidSetting("maxPlayers")
+	}
+
+	g.emit(&SettingsChanged{Settings: settings})
 	return nil
 }
 
+// KickPlayer 将玩家踢出游戏：等待状态下直接移出并退还买入金额，不产生动作记录；
+// 进行中的游戏按破产处理，被踢出的是当前玩家时进入下一个回合，只剩一位玩家时游戏结束
+func (g *Game) KickPlayer(playerID string) (*GameAction, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	player, exists := g.Players[playerID]
+	if !exists {
+		return nil, utils.ErrPlayerNotFound
+	}
+
+	if g.Status == StatusWaiting {
+		g.emit(&PlayerLeft{PlayerID: playerID})
+		return nil, nil
+	}
+
+	if g.Status != StatusPlaying {
+		return nil, utils.ErrInvalidGameState
+	}
+
+	if player.IsBankrupt() {
+		return nil, utils.ErrPlayerBankrupt
+	}
+
+	g.AddAction(&GameAction{
+		Type:      ActionKick,
+		PlayerID:  playerID,
+		Timestamp: g.now(),
+	})
+	action := g.bankrupt(player)
+	if g.activePlayerCount() <= 1 {
+		return action, g.EndGame()
+	}
+	if playerID == g.CurrentPlayerID {
+		return action, g.nextTurn()
+	}
+	return action, nil
+}
+
 // StartGame 开始游戏
 func (g *Game) StartGame() error {
 	g.mutex.Lock()
@@ -83,7 +253,7 @@ func (g *Game) StartGame() error {
 
 // 游戏开始前的检查
 func (g *Game) canStartGame() error {
-	if len(g.Players) < MinStartPlayers {
+	if len(g.Players) < g.Settings.MinPlayers {
 		return utils.ErrNotEnoughPlayers
 	}
 
@@ -96,57 +266,70 @@ func (g *Game) canStartGame() error {
 
 // 收集入场费
 func (g *Game) collectEntranceFees() error {
-	for _, player := range g.Players {
-		if player.Coins < InitialEntranceFee {
+	for _, id := range g.TurnOrder {
+		if g.Players[id].Coins < g.Settings.EntranceFee {
 			return utils.ErrInsufficientFunds
 		}
-		player.Coins -= InitialEntranceFee
-		g.PrizePool += InitialEntranceFee
+	}
+	for _, id := range g.TurnOrder {
+		g.transferCoins(id, AccountPrizePool, g.Settings.EntranceFee, "entranceFee")
 	}
 	return nil
 }
 
-// 初始化游戏状态
+// 初始化游戏状态，决定座位顺序后由第一位玩家开始
 func (g *Game) initializeGame() {
-	playerIDs := make([]string, 0, len(g.Players))
-	for id := range g.Players {
-		playerIDs = append(playerIDs, id)
-	}
-	sort.Strings(playerIDs)
-	g.CurrentPlayerID = playerIDs[0]
-
-	g.Status = StatusPlaying
-	g.StartTime = time.Now()
-	g.CurrentTurnStarted = time.Now()
+	g.decideTurnOrder()
+	g.emit(&GameStarted{FirstPlayerID: g.TurnOrder[0]})
 }
 
-// GetRemainingTime 获取游戏剩余时间（秒）
+// GetRemainingTime 获取游戏剩余时间（秒），暂停的游戏返回暂停时的剩余时间
 func (g *Game) GetRemainingTime() int {
-	if g.Status != StatusPlaying {
+	if g.Status != StatusPlaying && g.Status != StatusPaused {
 		return 0
 	}
-	remaining := GameTimeout - time.Since(g.StartTime)
+	remaining := time.Duration(g.Settings.GameTimeout) - g.timerNow().Sub(g.StartTime)
 	if remaining < 0 {
 		return 0
 	}
 	return int(remaining.Seconds())
 }
 
-// GetTurnTimeLeft 获取当前回合剩余时间（秒）
+// GetTurnTimeLeft 获取当前回合剩余时间（秒），暂停的游戏返回暂停时的剩余时间
 func (g *Game) GetTurnTimeLeft() int {
-	if g.Status != StatusPlaying {
+	if g.Status != StatusPlaying && g.Status != StatusPaused {
 		return 0
 	}
-	remaining := TurnTimeout - time.Since(g.CurrentTurnStarted)
+	remaining := time.Duration(g.Settings.TurnTimeout) - g.timerNow().Sub(g.CurrentTurnStarted)
 	if remaining < 0 {
 		return 0
 	}
 	return int(remaining.Seconds())
 }
 
-// AddAction 添加游戏动作记录
+// AddAction 添加游戏动作记录，调用方需持有游戏锁
 func (g *Game) AddAction(action *GameAction) {
-	g.Actions = append(g.Actions, action)
+	g.emit(&ActionRecorded{Action: action})
+}
+
+// GetSettings 获取规则设置的副本
+func (g *Game) GetSettings() GameSettings {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	settings := g.Settings
+	settings.PrizeRatios = append([]float64(nil), g.Settings.PrizeRatios...)
+	return settings
+}
+
+// GetEvents 获取事件日志的副本
+func (g *Game) GetEvents() []*Event {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	events := make([]*Event, len(g.Events))
+	copy(events, g.Events)
+	return events
 }
 
 // ... 保持之前的代码不变 ...
@@ -157,82 +340,144 @@ func (g *Game) EndGame() error {
 		return utils.ErrInvalidGameState
 	}
 
-	g.Status = StatusFinished
+	strategy, err := NewPayoutStrategy(g.Settings)
+	if err != nil {
+		return err
+	}
+
+	// 未处理的交易报价过期，进行中的拍卖立即成交
+	g.expirePendingTrades()
+	if auction := g.currentAuction(); auction != nil {
+		g.closeAuction(auction)
+	}
 
-	// 计算玩家总资产
-	type PlayerAsset struct {
-		PlayerID string
-		Total    int
+	// 结算未偿还的债务，无力偿还者破产；随后取回存款并偿还贷款
+	for _, id := range g.TurnOrder {
+		g.settleDebt(g.Players[id])
+	}
+	for _, id := range g.TurnOrder {
+		if player := g.Players[id]; !player.IsBankrupt() {
+			g.closeBankAccount(player)
+		}
 	}
-	assets := make([]PlayerAsset, 0, len(g.Players))
 
-	// 计算每个玩家的总资产（现金 + 地产价值）
-	for id, player := range g.Players {
-		total := player.Coins
-		// 加上地产价值
-		for _, tile := range g.Map.Tiles {
-			if tile.OwnerID == id {
-				total += tile.Price * (tile.Level + 1)
-			}
+	// 计算每个未破产玩家的总资产（现金 + 地产价值 - 未还清的贷款），破产玩家不参与奖池分配
+	standings := make([]Standing, 0, len(g.Players))
+	for seat, id := range g.TurnOrder {
+		player := g.Players[id]
+		if player.IsBankrupt() {
+			continue
 		}
-		assets = append(assets, PlayerAsset{id, total})
+		propertyValue, _ := g.propertyValue(id)
+		standings = append(standings, Standing{
+			PlayerID: id,
+			Total:    player.Coins + propertyValue - player.loanBalance(),
+			Seat:     seat,
+		})
 	}
+	rankStandings(standings)
 
-	// 按总资产排序
-	sort.Slice(assets, func(i, j int) bool {
-		return assets[i].Total > assets[j].Total
-	})
+	// 按规则设置的方式分配全部奖池
+	payouts := strategy.Payouts(standings, g.PrizePool)
+	for i, standing := range standings {
+		g.transferCoins(AccountPrizePool, standing.PlayerID, payouts[i], "prize")
+	}
 
-	// 分配奖池
-	prizeRatios := []float64{0.5, 0.3, 0.15, 0.05} // 奖池分配比例
-	for i, asset := range assets {
-		if i >= len(prizeRatios) {
-			break
+	// 所有玩家均已破产时奖池无人可分，按座位顺序平均退还给全部玩家，随游戏内余额一起结算回钱包
+	if len(standings) == 0 {
+		refunds := splitByWeights(make([]float64, len(g.TurnOrder)), g.PrizePool)
+		for i, id := range g.TurnOrder {
+			g.transferCoins(AccountPrizePool, id, refunds[i], "prizeRefund")
 		}
-		prize := int(float64(g.PrizePool) * prizeRatios[i])
-		g.Players[asset.PlayerID].Coins += prize
 	}
 
+	g.emit(&GameEnded{Ranking: g.finalRanking(standings)})
+
 	// 记录游戏结束动作
-	g.Actions = append(g.Actions, &GameAction{
+	g.AddAction(&GameAction{
 		Type:      "gameEnd",
-		Timestamp: time.Now(),
+		Timestamp: g.now(),
 	})
 
 	return nil
 }
 
-// GetFinalResults 获取游戏最终结果
+// ForceEnd 强制结束游戏：等待开始的游戏被放弃，进行中或暂停的游戏立即结束并分配奖池
+func (g *Game) ForceEnd() error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	switch g.Status {
+	case StatusWaiting:
+		g.emit(&GameAbandoned{})
+		return nil
+	case StatusPlaying, StatusPaused:
+		if g.Status == StatusPaused {
+			g.emit(&GameResumed{})
+		}
+		g.AddAction(&GameAction{
+			Type:      ActionForceEnd,
+			PlayerID:  g.CurrentPlayerID,
+			Timestamp: g.now(),
+		})
+		return g.EndGame()
+	}
+	return utils.ErrInvalidGameState
+}
+
+// GetFinalResults 获取游戏最终结果，按最终排名排列
 func (g *Game) GetFinalResults() ([]PlayerResult, error) {
 	if g.Status != StatusFinished {
 		return nil, utils.ErrInvalidGameState
 	}
 
-	results := make([]PlayerResult, 0, len(g.Players))
-	for id, player := range g.Players {
-		propertyValue := 0
-		propertyCount := 0
-		for _, tile := range g.Map.Tiles {
-			if tile.OwnerID == id {
-				propertyValue += tile.Price * (tile.Level + 1)
-				propertyCount++
-			}
-		}
+	results := make([]PlayerResult, 0, len(g.Ranking))
+	for i, id := range g.Ranking {
+		player := g.Players[id]
+		propertyValue, propertyCount := g.propertyValue(id)
 
 		results = append(results, PlayerResult{
+			Rank:          i + 1,
 			PlayerID:      id,
 			Name:          player.Name,
 			FinalCoins:    player.Coins,
 			PropertyValue: propertyValue,
 			PropertyCount: propertyCount,
-			TotalAssets:   player.Coins + propertyValue,
+			LoanBalance:   player.loanBalance(),
+			TotalAssets:   player.Coins + propertyValue - player.loanBalance(),
+			Bankrupt:      player.IsBankrupt(),
 		})
 	}
 
-	// 按总资产排序
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].TotalAssets > results[j].TotalAssets
-	})
-
 	return results, nil
 }
+
+// finalRanking 生成最终排名：参与奖池分配的玩家按名次排列，破产玩家按座位顺序排在最后
+func (g *Game) finalRanking(standings []Standing) []string {
+	ranking := make([]string, 0, len(g.TurnOrder))
+	for _, standing := range standings {
+		ranking = append(ranking, standing.PlayerID)
+	}
+	for _, id := range g.TurnOrder {
+		if g.Players[id].IsBankrupt() {
+			ranking = append(ranking, id)
+		}
+	}
+	return ranking
+}
+
+// propertyValue 计算玩家持有地产的总价值和数量，已抵押地产按扣除抵押本金后计算
+func (g *Game) propertyValue(playerID string) (int, int) {
+	value := 0
+	count := 0
+	for _, tile := range g.Map.Tiles {
+		if tile.OwnerID != playerID {
+			continue
+		}
+		if tileValue, err := tile.GetValue(g.Settings); err == nil {
+			value += tileValue
+			count++
+		}
+	}
+	return value, count
+}
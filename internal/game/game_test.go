@@ -0,0 +1,167 @@
+// internal/game/game_test.go
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"monopoly/pkg/utils"
+	"testing"
+	"time"
+)
+
+// testClock 可手动推进的时钟
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+// loadTestMap 加载指定名称的地图
+func loadTestMap(t *testing.T, name string) *GameMap {
+	t.Helper()
+
+	maps, err := LoadMaps("../../maps")
+	if err != nil {
+		t.Fatalf("load maps: %v", err)
+	}
+	m, err := maps.Get(name)
+	if err != nil {
+		t.Fatalf("get map %s: %v", name, err)
+	}
+	return m
+}
+
+// newTestGame 在小地图上使用默认设置创建并开始一局游戏，每位玩家持有5000金币
+func newTestGame(t *testing.T, seed int64, ids ...string) *Game {
+	t.Helper()
+
+	return newTestGameWithSettings(t, DefaultSettings(), seed, ids...)
+}
+
+// newTestGameWithSettings 在小地图上使用指定设置创建并开始一局游戏，每位玩家持有5000金币
+func newTestGameWithSettings(t *testing.T, settings GameSettings, seed int64, ids ...string) *Game {
+	t.Helper()
+
+	g := NewGame("g1", ids[0], seed, settings, loadTestMap(t, "small"), &testClock{now: time.Unix(1000, 0)})
+	for _, id := range ids {
+		if err := g.AddPlayer(NewPlayer(id, id, 5000)); err != nil {
+			t.Fatalf("add player %s: %v", id, err)
+		}
+	}
+	if err := g.StartGame(); err != nil {
+		t.Fatalf("start game: %v", err)
+	}
+	return g
+}
+
+// advanceClock 推进测试游戏的时钟
+func advanceClock(g *Game, d time.Duration) {
+	clock := g.clock.(*testClock)
+	clock.now = clock.now.Add(d)
+}
+
+// otherPlayer 获取当前玩家之后的下一位玩家
+func otherPlayer(g *Game) *Player {
+	return g.Players[g.getNextPlayerID(g.getOrderedPlayerIDs())]
+}
+
+// assertReplayMatches 检查重放事件日志得到的状态与当前游戏一致
+func assertReplayMatches(t *testing.T, g *Game) {
+	t.Helper()
+
+	replayed, err := Replay(g.Events, len(g.Events)-1)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	want, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("marshal game: %v", err)
+	}
+	got, err := json.Marshal(replayed)
+	if err != nil {
+		t.Fatalf("marshal replayed game: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("replayed state differs:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestReplayMatchesStartedGame(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b", "c")
+
+	if g.Status != StatusPlaying {
+		t.Fatalf("got status %s, want %s", g.Status, StatusPlaying)
+	}
+	if g.PrizePool != 3*g.Settings.EntranceFee {
+		t.Fatalf("got prize pool %d, want %d", g.PrizePool, 3*g.Settings.EntranceFee)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestUpdateSettingsBeforeStart(t *testing.T) {
+	g := NewGame("g1", "a", 1, DefaultSettings(), loadTestMap(t, "small"), &testClock{now: time.Unix(1000, 0)})
+	for _, id := range []string{"a", "b", "c"} {
+		if err := g.AddPlayer(NewPlayer(id, id, 5000)); err != nil {
+			t.Fatalf("add player %s: %v", id, err)
+		}
+	}
+
+	settings := g.GetSettings()
+	settings.MaxPlayers = 2
+	if err := g.UpdateSettings(settings); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("lower maxPlayers below joined players: got error %v, want %v", err, utils.ErrInvalidInput)
+	}
+
+	settings = g.GetSettings()
+	settings.BuyIn++
+	if err := g.UpdateSettings(settings); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("change buy-in after players joined: got error %v, want %v", err, utils.ErrInvalidInput)
+	}
+
+	settings = g.GetSettings()
+	settings.MaxPlayers = 3
+	settings.TurnOrder = TurnOrderRollOff
+	if err := g.UpdateSettings(settings); err != nil {
+		t.Fatalf("update settings: %v", err)
+	}
+	if err := g.StartGame(); err != nil {
+		t.Fatalf("start game: %v", err)
+	}
+	if g.Settings.MaxPlayers != 3 || g.Settings.TurnOrder != TurnOrderRollOff {
+		t.Fatalf("got settings %+v, want the updated settings", g.Settings)
+	}
+	assertReplayMatches(t, g)
+
+	if err := g.UpdateSettings(settings); !errors.Is(err, utils.ErrGameInProgress) {
+		t.Fatalf("update after start: got error %v, want %v", err, utils.ErrGameInProgress)
+	}
+}
+
+func TestVersionIncreasesWithEveryEvent(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	version, events := g.Version, len(g.Events)
+	changed := g.Changed(version)
+
+	if _, err := g.RollDice(g.CurrentPlayerID); err != nil {
+		t.Fatalf("roll dice: %v", err)
+	}
+	if g.Version != version+len(g.Events)-events {
+		t.Fatalf("got version %d after %d new events, want %d", g.Version, len(g.Events)-events, version+len(g.Events)-events)
+	}
+	select {
+	case <-changed:
+	default:
+		t.Fatal("change channel not closed after the version moved")
+	}
+
+	// 拒绝的操作不产生事件，版本不变
+	version = g.Version
+	if _, err := g.RollDice(otherPlayer(g).ID); err == nil {
+		t.Fatal("roll out of turn succeeded")
+	}
+	if g.Version != version {
+		t.Fatalf("rejected action moved the version from %d to %d", version, g.Version)
+	}
+}
@@ -5,44 +5,33 @@ import (
 	"monopoly/pkg/utils"
 )
 
+// GameMap 游戏地图，由 maps 目录下的 JSON 文件定义
 type GameMap struct {
-	Tiles []*Tile `json:"tiles"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Tiles       []*Tile            `json:"tiles"`
+	Groups      []*PropertyGroup   `json:"groups"`
+	Decks       map[string][]*Card `json:"decks,omitempty"` // 机会与命运牌堆的卡片定义
 }
 
-func NewDefaultMap() *GameMap {
-	tiles := []*Tile{
-		{
-			ID:   0,
-			Name: "起点",
-			Type: TileStart,
-		},
-		{
-			ID:        1,
-			Name:      "第一大道",
-			Type:      TileProperty,
-			Price:     200,
-			RentPrice: []int{20, 40, 80},
-		},
-		{
-			ID:   2,
-			Name: "机会",
-			Type: TileChance,
-		},
-		{
-			ID:        3,
-			Name:      "第二大道",
-			Type:      TileProperty,
-			Price:     300,
-			RentPrice: []int{30, 60, 120},
-		},
-		{
-			ID:   4,
-			Name: "银行",
-			Type: TileBank,
-		},
-	}
-
-	return &GameMap{Tiles: tiles}
+// PropertyGroup 地产组，同一玩家拥有整组地产时过路费按倍数加成
+type PropertyGroup struct {
+	Name      string `json:"name"`
+	Color     string `json:"color,omitempty"`
+	Positions []int  `json:"positions"` // 由地块的 Group 字段计算得出
+}
+
+// NewGameMap 根据地块和地产组创建地图，并计算各组包含的地块位置
+func NewGameMap(name string, tiles []*Tile, groups []*PropertyGroup) *GameMap {
+	for _, group := range groups {
+		group.Positions = make([]int, 0)
+		for _, tile := range tiles {
+			if tile.Type == TileProperty && tile.Group == group.Name {
+				group.Positions = append(group.Positions, tile.ID)
+			}
+		}
+	}
+	return &GameMap{Name: name, Tiles: tiles, Groups: groups}
 }
 
 func (m *GameMap) GetTile(position int) (*Tile, error) {
@@ -51,3 +40,68 @@ func (m *GameMap) GetTile(position int) (*Tile, error) {
 	}
 	return m.Tiles[position], nil
 }
+
+// GroupTiles 获取与指定地块同组的所有地块（包含自身），未分组时只返回自身
+func (m *GameMap) GroupTiles(tile *Tile) []*Tile {
+	if tile.Group == "" {
+		return []*Tile{tile}
+	}
+
+	tiles := make([]*Tile, 0)
+	for _, t := range m.Tiles {
+		if t.Type == TileProperty && t.Group == tile.Group {
+			tiles = append(tiles, t)
+		}
+	}
+	return tiles
+}
+
+// OwnsFullGroup 检查玩家是否拥有地块所在的整组地产
+func (m *GameMap) OwnsFullGroup(playerID string, tile *Tile) bool {
+	if tile.Group == "" {
+		return false
+	}
+
+	for _, t := range m.GroupTiles(tile) {
+		if t.OwnerID != playerID {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone 创建地图的深拷贝
+func (m *GameMap) Clone() *GameMap {
+	tiles := make([]*Tile, len(m.Tiles))
+	for i, tile := range m.Tiles {
+		tiles[i] = tile.Clone()
+	}
+
+	groups := make([]*PropertyGroup, len(m.Groups))
+	for i, group := range m.Groups {
+		groups[i] = &PropertyGroup{
+			Name:      group.Name,
+			Color:     group.Color,
+			Positions: append([]int(nil), group.Positions...),
+		}
+	}
+	var decks map[string][]*Card
+	if m.Decks != nil {
+		decks = make(map[string][]*Card, len(m.Decks))
+		for name, cards := range m.Decks {
+			decks[name] = make([]*Card, len(cards))
+			for i, card := range cards {
+				c := *card
+				decks[name][i] = &c
+			}
+		}
+	}
+
+	return &GameMap{
+		Name:        m.Name,
+		Description: m.Description,
+		Tiles:       tiles,
+		Groups:      groups,
+		Decks:       decks,
+	}
+}
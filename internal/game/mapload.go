@@ -0,0 +1,181 @@
+// internal/game/mapload.go
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"monopoly/pkg/utils"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DefaultMapName 未指定地图时使用的地图名称
+const DefaultMapName = "default"
+
+// MapRegistry 启动时加载的地图集合
+type MapRegistry struct {
+	maps map[string]*GameMap
+}
+
+// LoadMaps 加载并验证目录下所有 .json 地图文件
+func LoadMaps(dir string) (*MapRegistry, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	registry := &MapRegistry{maps: make(map[string]*GameMap)}
+	for _, path := range paths {
+		m, err := LoadMapFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if _, exists := registry.maps[m.Name]; exists {
+			return nil, fmt.Errorf("%s: %w: duplicate map name %q", path, utils.ErrInvalidMap, m.Name)
+		}
+		registry.maps[m.Name] = m
+	}
+
+	if _, exists := registry.maps[DefaultMapName]; !exists {
+		return nil, fmt.Errorf("%s: %w: missing %q map", dir, utils.ErrInvalidMap, DefaultMapName)
+	}
+
+	return registry, nil
+}
+
+// LoadMapFile 加载并验证单个地图文件
+func LoadMapFile(path string) (*GameMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var def GameMap
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("%w: %v", utils.ErrInvalidMap, err)
+	}
+
+	m := NewGameMap(def.Name, def.Tiles, def.Groups)
+	m.Description = def.Description
+	m.Decks = def.Decks
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Get 获取指定名称地图的副本，名称为空时返回默认地图
+func (r *MapRegistry) Get(name string) (*GameMap, error) {
+	if name == "" {
+		name = DefaultMapName
+	}
+
+	m, exists := r.maps[name]
+	if !exists {
+		return nil, utils.ErrMapNotFound
+	}
+	return m.Clone(), nil
+}
+
+// List 获取所有地图的副本，按名称排序
+func (r *MapRegistry) List() []*GameMap {
+	names := make([]string, 0, len(r.maps))
+	for name := range r.maps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	maps := make([]*GameMap, len(names))
+	for i, name := range names {
+		maps[i] = r.maps[name].Clone()
+	}
+	return maps
+}
+
+// Validate 验证地图定义：编号连续、唯一的起点位于0号格、地产价格有效、各地产过路费等级数一致且卡片定义有效
+func (m *GameMap) Validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("%w: missing name", utils.ErrInvalidMap)
+	}
+
+	if len(m.Tiles) == 0 {
+		return fmt.Errorf("%w: no tiles", utils.ErrInvalidMap)
+	}
+
+	groups := make(map[string]bool)
+	for _, group := range m.Groups {
+		if group.Name == "" || groups[group.Name] {
+			return fmt.Errorf("%w: invalid or duplicate group %q", utils.ErrInvalidMap, group.Name)
+		}
+		groups[group.Name] = true
+		if len(group.Positions) == 0 {
+			return fmt.Errorf("%w: group %q has no properties", utils.ErrInvalidMap, group.Name)
+		}
+	}
+
+	startCount := 0
+	rentLevels := 0
+	for i, tile := range m.Tiles {
+		if tile == nil || tile.ID != i {
+			return fmt.Errorf("%w: tile ids must be sequential from 0 (at index %d)", utils.ErrInvalidMap, i)
+		}
+
+		if tile.OwnerID != "" || tile.Level != 0 || tile.Mortgaged {
+			return fmt.Errorf("%w: tile %d must not define ownership state", utils.ErrInvalidMap, i)
+		}
+
+		switch tile.Type {
+		case TileStart:
+			startCount++
+		case TileProperty:
+			if tile.Price <= 0 {
+				return fmt.Errorf("%w: property %d must have a positive price", utils.ErrInvalidMap, i)
+			}
+			if len(tile.RentPrice) == 0 {
+				return fmt.Errorf("%w: property %d has no rent prices", utils.ErrInvalidMap, i)
+			}
+			if rentLevels == 0 {
+				rentLevels = len(tile.RentPrice)
+			}
+			if len(tile.RentPrice) != rentLevels {
+				return fmt.Errorf("%w: property %d has %d rent levels, expected %d",
+					utils.ErrInvalidMap, i, len(tile.RentPrice), rentLevels)
+			}
+			for _, rent := range tile.RentPrice {
+				if rent < 0 {
+					return fmt.Errorf("%w: property %d has a negative rent", utils.ErrInvalidMap, i)
+				}
+			}
+			if tile.Group != "" && !groups[tile.Group] {
+				return fmt.Errorf("%w: property %d references unknown group %q", utils.ErrInvalidMap, i, tile.Group)
+			}
+		case TileChance, TileFate, TileBank, TilePrison, TileGoToPrison:
+			if tile.Price != 0 || len(tile.RentPrice) != 0 || tile.Group != "" {
+				return fmt.Errorf("%w: tile %d is not a property", utils.ErrInvalidMap, i)
+			}
+		default:
+			return fmt.Errorf("%w: tile %d has unknown type %q", utils.ErrInvalidMap, i, tile.Type)
+		}
+	}
+
+	if startCount != 1 || m.Tiles[0].Type != TileStart {
+		return fmt.Errorf("%w: exactly one start tile is required at position 0", utils.ErrInvalidMap)
+	}
+
+	if m.hasTile(TileGoToPrison) && !m.hasTile(TilePrison) {
+		return fmt.Errorf("%w: a go-to-prison tile requires a prison tile", utils.ErrInvalidMap)
+	}
+
+	return m.validateDecks()
+}
+
+// hasTile 检查地图是否包含指定类型的地块
+func (m *GameMap) hasTile(tileType TileType) bool {
+	for _, tile := range m.Tiles {
+		if tile.Type == tileType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+// internal/game/mapload_test.go
+package game
+
+import (
+	"errors"
+	"monopoly/pkg/utils"
+	"testing"
+)
+
+func TestLoadedMapsAreValid(t *testing.T) {
+	maps, err := LoadMaps("../../maps")
+	if err != nil {
+		t.Fatalf("load maps: %v", err)
+	}
+	for _, m := range maps.List() {
+		if err := m.Validate(); err != nil {
+			t.Errorf("map %s: %v", m.Name, err)
+		}
+	}
+}
+
+func TestValidateRejectsInvalidMaps(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(m *GameMap)
+	}{
+		{"missing name", func(m *GameMap) { m.Name = "" }},
+		{"no tiles", func(m *GameMap) { m.Tiles = nil }},
+		{"duplicate group", func(m *GameMap) { m.Groups = append(m.Groups, m.Groups[0]) }},
+		{"empty group", func(m *GameMap) {
+			m.Groups = append(m.Groups, &PropertyGroup{Name: "empty"})
+		}},
+		{"non-sequential ids", func(m *GameMap) { m.Tiles[3].ID = 5 }},
+		{"nil tile", func(m *GameMap) { m.Tiles[3] = nil }},
+		{"ownership state", func(m *GameMap) { m.Tiles[1].OwnerID = "player" }},
+		{"non-positive price", func(m *GameMap) { m.Tiles[1].Price = 0 }},
+		{"no rent prices", func(m *GameMap) { m.Tiles[1].RentPrice = nil }},
+		{"unequal rent levels", func(m *GameMap) {
+			m.Tiles[3].RentPrice = append(m.Tiles[3].RentPrice, 1000)
+		}},
+		{"negative rent", func(m *GameMap) { m.Tiles[1].RentPrice[0] = -1 }},
+		{"unknown group", func(m *GameMap) { m.Tiles[1].Group = "purple" }},
+		{"priced non-property", func(m *GameMap) { m.Tiles[4].Price = 100 }},
+		{"unknown tile type", func(m *GameMap) { m.Tiles[4].Type = "casino" }},
+		{"missing start", func(m *GameMap) { m.Tiles[0].Type = TileBank }},
+		{"misplaced start", func(m *GameMap) {
+			m.Tiles[0].Type = TileBank
+			m.Tiles[4].Type = TileStart
+		}},
+		{"two starts", func(m *GameMap) { m.Tiles[4].Type = TileStart }},
+		{"go-to-prison without prison", func(m *GameMap) { m.Tiles[9].Type = TileBank }},
+		{"empty deck", func(m *GameMap) { m.Decks[DeckChance] = nil }},
+	}
+
+	base := loadTestMap(t, DefaultMapName)
+	if base.Tiles[0].Type != TileStart || base.Tiles[1].Type != TileProperty || base.Tiles[3].Type != TileProperty ||
+		base.Tiles[4].Type != TileBank || base.Tiles[9].Type != TilePrison || !base.hasTile(TileGoToPrison) {
+		t.Fatal("default map layout changed, update the test cases")
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := base.Clone()
+			tt.mutate(m)
+			if err := m.Validate(); !errors.Is(err, utils.ErrInvalidMap) {
+				t.Fatalf("got error %v, want %v", err, utils.ErrInvalidMap)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// internal/game/mortgage.go
+package game
+
+import (
+	"monopoly/pkg/utils"
+)
+
+// MortgageProperty 抵押地产，从奖池获得抵押金额
+func (g *Game) MortgageProperty(playerID string, position int) (*GameAction, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if err := g.validateGameState(playerID); err != nil {
+		return nil, err
+	}
+
+	tile, err := g.validateOwnedProperty(playerID, position)
+	if err != nil {
+		return nil, err
+	}
+
+	if tile.Mortgaged {
+		return nil, utils.ErrPropertyMortgaged
+	}
+
+	if tile.Level > 0 {
+		return nil, utils.ErrPropertyHasUpgrades
+	}
+
+	amount := tile.GetMortgageValue(g.Settings)
+	if g.PrizePool < amount {
+		return nil, utils.ErrInsufficientFunds
+	}
+
+	g.transferCoins(AccountPrizePool, playerID, amount, "mortgage")
+	g.emit(&PropertyMortgageChanged{Position: position, Mortgaged: true})
+
+	action := &GameAction{
+		Type:      ActionMortgage,
+		PlayerID:  playerID,
+		Position:  position,
+		Amount:    amount,
+		Timestamp: g.now(),
+	}
+
+	g.AddAction(action)
+	return action, nil
+}
+
+// UnmortgageProperty 赎回地产，需支付本金和利息
+func (g *Game) UnmortgageProperty(playerID string, position int) (*GameAction, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if err := g.validateGameState(playerID); err != nil {
+		return nil, err
+	}
+
+	tile, err := g.validateOwnedProperty(playerID, position)
+	if err != nil {
+		return nil, err
+	}
+
+	if !tile.Mortgaged {
+		return nil, utils.ErrPropertyNotMortgaged
+	}
+
+	player := g.Players[playerID]
+	if player.InDebt() {
+		return nil, utils.ErrOutstandingDebt
+	}
+
+	cost := tile.GetUnmortgageCost(g.Settings)
+	if player.Coins < cost {
+		return nil, utils.ErrInsufficientFunds
+	}
+
+	g.transferCoins(playerID, AccountPrizePool, cost, "unmortgage")
+	g.emit(&PropertyMortgageChanged{Position: position, Mortgaged: false})
+
+	action := &GameAction{
+		Type:      ActionUnmortgage,
+		PlayerID:  playerID,
+		Position:  position,
+		Amount:    cost,
+		Timestamp: g.now(),
+	}
+
+	g.AddAction(action)
+	return action, nil
+}
+
+// validateOwnedProperty 验证指定位置是玩家拥有的地产
+func (g *Game) validateOwnedProperty(playerID string, position int) (*Tile, error) {
+	tile, err := g.Map.GetTile(position)
+	if err != nil {
+		return nil, err
+	}
+
+	if tile.Type != TileProperty {
+		return nil, utils.ErrNotProperty
+	}
+
+	if tile.OwnerID != playerID {
+		return nil, utils.ErrNotOwner
+	}
+
+	return tile, nil
+}
@@ -0,0 +1,131 @@
+// internal/game/mortgage_test.go
+package game
+
+import (
+	"errors"
+	"monopoly/pkg/utils"
+	"testing"
+)
+
+func TestMortgageAndUnmortgage(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+	g.emit(&PropertyOwnerChanged{Position: 1, OwnerID: player.ID})
+	tile := g.Map.Tiles[1]
+	coins, prizePool := player.Coins, g.PrizePool
+
+	action, err := g.MortgageProperty(player.ID, 1)
+	if err != nil {
+		t.Fatalf("mortgage: %v", err)
+	}
+	principal := tile.Price / 2
+	if !tile.Mortgaged || action.Amount != principal || player.Coins != coins+principal || g.PrizePool != prizePool-principal {
+		t.Fatalf("got mortgaged %v, amount %d, coins %d and prize pool %d, want %d paid from the prize pool",
+			tile.Mortgaged, action.Amount, player.Coins, g.PrizePool, principal)
+	}
+	if _, err := g.MortgageProperty(player.ID, 1); !errors.Is(err, utils.ErrPropertyMortgaged) {
+		t.Fatalf("mortgage twice: got error %v, want %v", err, utils.ErrPropertyMortgaged)
+	}
+	if _, err := g.UpgradeProperty(player.ID, 1); !errors.Is(err, utils.ErrPropertyMortgaged) {
+		t.Fatalf("upgrade mortgaged property: got error %v, want %v", err, utils.ErrPropertyMortgaged)
+	}
+
+	// 赎回需支付本金和利息
+	action, err = g.UnmortgageProperty(player.ID, 1)
+	if err != nil {
+		t.Fatalf("unmortgage: %v", err)
+	}
+	cost := principal + principal/10
+	if tile.Mortgaged || action.Amount != cost || player.Coins != coins+principal-cost || g.PrizePool != prizePool-principal+cost {
+		t.Fatalf("got mortgaged %v, amount %d, coins %d and prize pool %d, want %d paid to the prize pool",
+			tile.Mortgaged, action.Amount, player.Coins, g.PrizePool, cost)
+	}
+	if _, err := g.UnmortgageProperty(player.ID, 1); !errors.Is(err, utils.ErrPropertyNotMortgaged) {
+		t.Fatalf("unmortgage twice: got error %v, want %v", err, utils.ErrPropertyNotMortgaged)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestMortgageRejectsInvalidProperty(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+	g.emit(&PropertyOwnerChanged{Position: 1, OwnerID: otherPlayer(g).ID})
+	g.emit(&PropertyOwnerChanged{Position: 2, OwnerID: player.ID})
+	g.emit(&PropertyLevelChanged{Position: 2, Level: 1})
+
+	if _, err := g.MortgageProperty(player.ID, 1); !errors.Is(err, utils.ErrNotOwner) {
+		t.Fatalf("mortgage another player's property: got error %v, want %v", err, utils.ErrNotOwner)
+	}
+	if _, err := g.MortgageProperty(player.ID, 3); !errors.Is(err, utils.ErrNotProperty) {
+		t.Fatalf("mortgage a chance tile: got error %v, want %v", err, utils.ErrNotProperty)
+	}
+	if _, err := g.MortgageProperty(player.ID, 2); !errors.Is(err, utils.ErrPropertyHasUpgrades) {
+		t.Fatalf("mortgage upgraded property: got error %v, want %v", err, utils.ErrPropertyHasUpgrades)
+	}
+
+	// 余额不足以支付赎回费用时不能赎回
+	g.emit(&PropertyLevelChanged{Position: 2, Level: 0})
+	if _, err := g.MortgageProperty(player.ID, 2); err != nil {
+		t.Fatalf("mortgage: %v", err)
+	}
+	g.transferCoins(player.ID, AccountPrizePool, player.Coins, "test")
+	if _, err := g.UnmortgageProperty(player.ID, 2); !errors.Is(err, utils.ErrInsufficientFunds) {
+		t.Fatalf("unmortgage without coins: got error %v, want %v", err, utils.ErrInsufficientFunds)
+	}
+	if !g.Map.Tiles[2].Mortgaged {
+		t.Fatal("rejected unmortgage released the property")
+	}
+}
+
+func TestMortgagedPropertyCollectsNoRent(t *testing.T) {
+	g := newTestGameWithDice(t, func(dice []int) bool { return dice[0]+dice[1] == 4 }, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+	owner := otherPlayer(g)
+	g.emit(&PropertyOwnerChanged{Position: 4, OwnerID: owner.ID})
+	g.emit(&PropertyMortgageChanged{Position: 4, Mortgaged: true})
+	coins, ownerCoins := player.Coins, owner.Coins
+
+	if _, err := g.RollDice(player.ID); err != nil {
+		t.Fatalf("roll dice: %v", err)
+	}
+	if player.Position != 4 {
+		t.Fatalf("landed on %d, want 4", player.Position)
+	}
+	if player.Coins != coins || owner.Coins != ownerCoins || player.InDebt() {
+		t.Fatalf("got coins %d/%d and debts %+v, want no rent paid", player.Coins, owner.Coins, player.Debts)
+	}
+}
+
+func TestFinalResultsCountMortgagedPropertyAtEquity(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+	g.emit(&PropertyOwnerChanged{Position: 1, OwnerID: player.ID})
+	g.emit(&PropertyOwnerChanged{Position: 2, OwnerID: player.ID})
+	if _, err := g.MortgageProperty(player.ID, 1); err != nil {
+		t.Fatalf("mortgage: %v", err)
+	}
+
+	if err := g.ForceEnd(); err != nil {
+		t.Fatalf("force end: %v", err)
+	}
+	results, err := g.GetFinalResults()
+	if err != nil {
+		t.Fatalf("final results: %v", err)
+	}
+
+	// 已抵押的地产按原价扣除抵押本金计入总资产
+	want := g.Map.Tiles[1].Price/2 + g.Map.Tiles[2].Price
+	for _, result := range results {
+		if result.PlayerID != player.ID {
+			continue
+		}
+		if result.PropertyValue != want || result.PropertyCount != 2 {
+			t.Fatalf("got property value %d over %d properties, want %d over 2", result.PropertyValue, result.PropertyCount, want)
+		}
+		if result.TotalAssets != result.FinalCoins+want {
+			t.Fatalf("got total assets %d, want %d", result.TotalAssets, result.FinalCoins+want)
+		}
+		return
+	}
+	t.Fatalf("player %s missing from results %+v", player.ID, results)
+}
@@ -0,0 +1,33 @@
+// internal/game/pause.go
+package game
+
+import (
+	"monopoly/pkg/utils"
+)
+
+// Pause 以指定原因暂停进行中的游戏，暂停期间不能操作，回合和游戏计时停止
+func (g *Game) Pause(reason PauseReason) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.Status != StatusPlaying {
+		return utils.ErrInvalidGameState
+	}
+
+	g.emit(&GamePaused{Reason: reason})
+	return nil
+}
+
+// Resume 恢复因指定原因暂停的游戏，当前回合保留暂停时剩余的时间
+// 因其他原因暂停的游戏不受影响，例如重启后不会恢复主持人暂停的游戏
+func (g *Game) Resume(reason PauseReason) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.Status != StatusPaused || g.PauseReason != reason {
+		return utils.ErrInvalidGameState
+	}
+
+	g.emit(&GameResumed{})
+	return nil
+}
@@ -0,0 +1,85 @@
+// internal/game/pause_test.go
+package game
+
+import (
+	"errors"
+	"monopoly/pkg/utils"
+	"testing"
+	"time"
+)
+
+func TestResumeOnlyMatchingPauseReason(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	if err := g.Pause(PauseHost); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if err := g.Pause(PauseShutdown); !errors.Is(err, utils.ErrInvalidGameState) {
+		t.Fatalf("pause paused game: got error %v, want %v", err, utils.ErrInvalidGameState)
+	}
+	if err := g.Resume(PauseShutdown); !errors.Is(err, utils.ErrInvalidGameState) {
+		t.Fatalf("resume host pause as shutdown: got error %v, want %v", err, utils.ErrInvalidGameState)
+	}
+	if err := g.Resume(PauseHost); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if g.Status != StatusPlaying || g.PauseReason != "" {
+		t.Fatalf("got status %s paused by %q, want playing", g.Status, g.PauseReason)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestForceEndPausedGame(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	if err := g.Pause(PauseHost); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+
+	if err := g.ForceEnd(); err != nil {
+		t.Fatalf("force end: %v", err)
+	}
+	if g.Status != StatusFinished || g.PrizePool != 0 {
+		t.Fatalf("got status %s and prize pool %d, want finished with the pool paid out", g.Status, g.PrizePool)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestPauseKeepsRemainingTurnTime(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	current := g.CurrentPlayerID
+	advanceClock(g, 10*time.Second)
+	turnLeft, gameLeft := g.GetTurnTimeLeft(), g.GetRemainingTime()
+
+	if err := g.Pause(PauseShutdown); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	advanceClock(g, time.Hour)
+	if err := g.CheckTimeouts(); err != nil {
+		t.Fatalf("check timeouts: %v", err)
+	}
+	if g.CurrentPlayerID != current || g.Status != StatusPaused {
+		t.Fatalf("paused game moved on: status %s, current player %s", g.Status, g.CurrentPlayerID)
+	}
+	if _, err := g.RollDice(current); !errors.Is(err, utils.ErrInvalidGameState) {
+		t.Fatalf("roll while paused: got error %v, want %v", err, utils.ErrInvalidGameState)
+	}
+	if g.GetTurnTimeLeft() != turnLeft || g.GetRemainingTime() != gameLeft {
+		t.Fatalf("paused timers ran: turn %d/%d, game %d/%d", g.GetTurnTimeLeft(), turnLeft, g.GetRemainingTime(), gameLeft)
+	}
+
+	// 恢复后从暂停时的剩余时间继续计时
+	if err := g.Resume(PauseShutdown); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if g.GetTurnTimeLeft() != turnLeft || g.GetRemainingTime() != gameLeft {
+		t.Fatalf("got turn %ds and game %ds left after resume, want %ds and %ds",
+			g.GetTurnTimeLeft(), g.GetRemainingTime(), turnLeft, gameLeft)
+	}
+	advanceClock(g, time.Duration(turnLeft)*time.Second)
+	if err := g.CheckTimeouts(); err != nil {
+		t.Fatalf("check timeouts: %v", err)
+	}
+	if g.CurrentPlayerID == current {
+		t.Fatal("turn did not time out once the remaining time ran out")
+	}
+	assertReplayMatches(t, g)
+}
@@ -0,0 +1,147 @@
+// internal/game/payout.go
+package game
+
+import (
+	"fmt"
+	"monopoly/pkg/utils"
+	"sort"
+)
+
+// PayoutScheme 奖池分配方式
+type PayoutScheme string
+
+const (
+	PayoutWinnerTakesAll PayoutScheme = "winnerTakesAll" // 第一名获得全部奖池
+	PayoutTopN           PayoutScheme = "topN"           // 前N名按 PrizeRatios 分配
+	PayoutProportional   PayoutScheme = "proportional"   // 按总资产占比分配
+	PayoutRankShared     PayoutScheme = "rankShared"     // 按名次比例分配，总资产相同的玩家平分所占名次的比例之和
+)
+
+// Standing 参与奖池分配的玩家排名，按总资产从高到低、资产相同时按座位顺序排列
+type Standing struct {
+	PlayerID string `json:"playerId"`
+	Total    int    `json:"total"`
+	Seat     int    `json:"seat"`
+}
+
+// PayoutStrategy 奖池分配策略，返回与 standings 一一对应的奖金，总和必须等于 pool
+type PayoutStrategy interface {
+	Payouts(standings []Standing, pool int) []int
+}
+
+// NewPayoutStrategy 根据规则设置创建奖池分配策略
+func NewPayoutStrategy(s GameSettings) (PayoutStrategy, error) {
+	switch s.PayoutScheme {
+	case PayoutWinnerTakesAll:
+		return winnerTakesAll{}, nil
+	case PayoutTopN:
+		return topN{ratios: s.PrizeRatios}, nil
+	case PayoutProportional:
+		return proportional{}, nil
+	case PayoutRankShared:
+		return rankShared{ratios: s.PrizeRatios}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown payout scheme %q", utils.ErrInvalidInput, s.PayoutScheme)
+}
+
+// rankStandings 对玩家排名：总资产从高到低，相同时座位靠前者优先
+func rankStandings(standings []Standing) {
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Total != standings[j].Total {
+			return standings[i].Total > standings[j].Total
+		}
+		return standings[i].Seat < standings[j].Seat
+	})
+}
+
+// splitByWeights 按权重向下取整分配奖池，取整剩余的金币归排名第一的玩家
+// 权重全为0时平均分配
+func splitByWeights(weights []float64, pool int) []int {
+	payouts := make([]int, len(weights))
+	if len(weights) == 0 {
+		return payouts
+	}
+
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	if total <= 0 {
+		for i := range weights {
+			weights[i] = 1
+		}
+		total = float64(len(weights))
+	}
+
+	paid := 0
+	for i, w := range weights {
+		payouts[i] = int(float64(pool) * w / total)
+		paid += payouts[i]
+	}
+	payouts[0] += pool - paid
+	return payouts
+}
+
+// winnerTakesAll 第一名获得全部奖池
+type winnerTakesAll struct{}
+
+func (winnerTakesAll) Payouts(standings []Standing, pool int) []int {
+	weights := make([]float64, len(standings))
+	if len(weights) > 0 {
+		weights[0] = 1
+	}
+	return splitByWeights(weights, pool)
+}
+
+// topN 前N名按比例分配，参与人数少于N时按实际人数的比例重新归一
+type topN struct {
+	ratios []float64
+}
+
+func (t topN) Payouts(standings []Standing, pool int) []int {
+	weights := make([]float64, len(standings))
+	for i := range weights {
+		if i < len(t.ratios) {
+			weights[i] = t.ratios[i]
+		}
+	}
+	return splitByWeights(weights, pool)
+}
+
+// proportional 按总资产占比分配，资产为负的玩家不参与分配
+type proportional struct{}
+
+func (proportional) Payouts(standings []Standing, pool int) []int {
+	weights := make([]float64, len(standings))
+	for i, s := range standings {
+		if s.Total > 0 {
+			weights[i] = float64(s.Total)
+		}
+	}
+	return splitByWeights(weights, pool)
+}
+
+// rankShared 按名次比例分配，总资产相同的玩家平分所占名次的比例之和
+type rankShared struct {
+	ratios []float64
+}
+
+func (r rankShared) Payouts(standings []Standing, pool int) []int {
+	weights := make([]float64, len(standings))
+	for start := 0; start < len(standings); {
+		end := start + 1
+		for end < len(standings) && standings[end].Total == standings[start].Total {
+			end++
+		}
+
+		shared := 0.0
+		for i := start; i < end && i < len(r.ratios); i++ {
+			shared += r.ratios[i]
+		}
+		for i := start; i < end; i++ {
+			weights[i] = shared / float64(end-start)
+		}
+		start = end
+	}
+	return splitByWeights(weights, pool)
+}
@@ -0,0 +1,195 @@
+// internal/game/payout_test.go
+package game
+
+import (
+	"errors"
+	"monopoly/pkg/utils"
+	"reflect"
+	"testing"
+)
+
+func TestPayoutStrategies(t *testing.T) {
+	standings := []Standing{
+		{PlayerID: "a", Total: 6000, Seat: 1},
+		{PlayerID: "b", Total: 3000, Seat: 0},
+		{PlayerID: "c", Total: 1000, Seat: 2},
+	}
+	ratios := []float64{0.5, 0.3, 0.15, 0.05}
+
+	tests := []struct {
+		name     string
+		strategy PayoutStrategy
+		pool     int
+		want     []int
+	}{
+		{"winnerTakesAll", winnerTakesAll{}, 1000, []int{1000, 0, 0}},
+		// 三人参与时按 0.5:0.3:0.15 重新归一，取整剩余归第一名
+		{"topN", topN{ratios: ratios}, 1000, []int{528, 315, 157}},
+		{"topN two ratios", topN{ratios: []float64{0.7, 0.3}}, 1000, []int{700, 300, 0}},
+		{"proportional", proportional{}, 1000, []int{600, 300, 100}},
+		{"rankShared", rankShared{ratios: ratios}, 1000, []int{528, 315, 157}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.strategy.Payouts(standings, tt.pool)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got payouts %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPayoutsSumToPool(t *testing.T) {
+	standings := []Standing{
+		{PlayerID: "a", Total: 3333, Seat: 0},
+		{PlayerID: "b", Total: 3333, Seat: 1},
+		{PlayerID: "c", Total: 1, Seat: 2},
+	}
+	strategies := map[string]PayoutStrategy{
+		"winnerTakesAll": winnerTakesAll{},
+		"topN":           topN{ratios: []float64{0.5, 0.3, 0.2}},
+		"proportional":   proportional{},
+		"rankShared":     rankShared{ratios: []float64{0.5, 0.3, 0.2}},
+	}
+
+	for name, strategy := range strategies {
+		for _, pool := range []int{0, 1, 7, 1001, 99999} {
+			total := 0
+			for _, payout := range strategy.Payouts(standings, pool) {
+				if payout < 0 {
+					t.Fatalf("%s: negative payout for pool %d", name, pool)
+				}
+				total += payout
+			}
+			if total != pool {
+				t.Fatalf("%s: payouts sum to %d, want %d", name, total, pool)
+			}
+		}
+	}
+}
+
+func TestRankSharedSplitsTies(t *testing.T) {
+	standings := []Standing{
+		{PlayerID: "a", Total: 5000, Seat: 0},
+		{PlayerID: "b", Total: 5000, Seat: 1},
+		{PlayerID: "c", Total: 1000, Seat: 2},
+	}
+
+	// 并列第一的玩家平分第一、二名的比例之和
+	got := rankShared{ratios: []float64{0.6, 0.3, 0.1}}.Payouts(standings, 1000)
+	if want := []int{450, 450, 100}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got payouts %v, want %v", got, want)
+	}
+}
+
+func TestProportionalSkipsNegativeTotals(t *testing.T) {
+	standings := []Standing{
+		{PlayerID: "a", Total: 0, Seat: 0},
+		{PlayerID: "b", Total: -500, Seat: 1},
+	}
+
+	// 没有玩家资产为正时平均分配
+	got := proportional{}.Payouts(standings, 1001)
+	if want := []int{501, 500}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got payouts %v, want %v", got, want)
+	}
+
+	standings[0].Total = 100
+	got = proportional{}.Payouts(standings, 1000)
+	if want := []int{1000, 0}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got payouts %v, want %v", got, want)
+	}
+}
+
+func TestRankStandings(t *testing.T) {
+	standings := []Standing{
+		{PlayerID: "c", Total: 1000, Seat: 2},
+		{PlayerID: "b", Total: 3000, Seat: 1},
+		{PlayerID: "a", Total: 3000, Seat: 0},
+	}
+	rankStandings(standings)
+
+	got := make([]string, len(standings))
+	for i, s := range standings {
+		got[i] = s.PlayerID
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got ranking %v, want %v", got, want)
+	}
+}
+
+func TestNewPayoutStrategyRejectsUnknownScheme(t *testing.T) {
+	settings := DefaultSettings()
+	settings.PayoutScheme = "lottery"
+
+	if _, err := NewPayoutStrategy(settings); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("got error %v, want %v", err, utils.ErrInvalidInput)
+	}
+}
+
+func TestEndGamePaysOutPrizePool(t *testing.T) {
+	settings := DefaultSettings()
+	settings.PayoutScheme = PayoutWinnerTakesAll
+	g := newTestGameWithSettings(t, settings, 1, "a", "b", "c")
+
+	bankrupt := g.Players[g.CurrentPlayerID]
+	if _, err := g.DeclareBankruptcy(bankrupt.ID); err != nil {
+		t.Fatalf("declare bankruptcy: %v", err)
+	}
+	winner := g.Players[g.CurrentPlayerID]
+	runnerUp := otherPlayer(g)
+	g.emit(&PropertyOwnerChanged{Position: 10, OwnerID: winner.ID})
+	coins, pool := winner.Coins, g.PrizePool
+
+	if err := g.ForceEnd(); err != nil {
+		t.Fatalf("force end: %v", err)
+	}
+	if g.PrizePool != 0 || winner.Coins != coins+pool {
+		t.Fatalf("got prize pool %d and winner coins %d, want 0 and %d", g.PrizePool, winner.Coins, coins+pool)
+	}
+	if want := []string{winner.ID, runnerUp.ID, bankrupt.ID}; !reflect.DeepEqual(g.Ranking, want) {
+		t.Fatalf("got ranking %v, want %v", g.Ranking, want)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestEndGameRefundsPrizePoolWhenAllBankrupt(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b", "c")
+	for _, id := range g.TurnOrder {
+		player := g.Players[id]
+		g.incurDebt(player, AccountPrizePool, player.Coins+1, "card:chance-fine")
+	}
+
+	if err := g.ForceEnd(); err != nil {
+		t.Fatalf("force end: %v", err)
+	}
+	for _, id := range g.TurnOrder {
+		if !g.Players[id].IsBankrupt() {
+			t.Fatalf("player %s not bankrupt", id)
+		}
+	}
+
+	// 奖池无人可分时按座位平均退还，取整剩余归座位最前的玩家
+	pool := 0
+	for _, id := range g.TurnOrder {
+		pool += g.Players[id].Coins
+	}
+	if g.PrizePool != 0 || pool == 0 {
+		t.Fatalf("got prize pool %d and %d coins refunded, want the whole pool refunded", g.PrizePool, pool)
+	}
+	share := pool / len(g.TurnOrder)
+	for seat, id := range g.TurnOrder {
+		want := share
+		if seat == 0 {
+			want += pool - share*len(g.TurnOrder)
+		}
+		if coins := g.Players[id].Coins; coins != want {
+			t.Fatalf("player %s refunded %d, want %d", id, coins, want)
+		}
+	}
+	if len(g.Ranking) != len(g.TurnOrder) {
+		t.Fatalf("got ranking %v, want every player listed", g.Ranking)
+	}
+	assertReplayMatches(t, g)
+}
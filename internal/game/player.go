@@ -7,15 +7,30 @@ import (
 
 // Player 表示游戏中的玩家
 type Player struct {
-	ID         string       `json:"id"`
-	Name       string       `json:"name"`
-	Coins      int          `json:"coins"`
-	Position   int          `json:"position"`
-	Status     PlayerStatus `json:"status"`
-	HasRolled  bool         `json:"hasRolled"`  // 是否已经掷过骰子
-	InPrison   bool         `json:"inPrison"`   // 是否在监狱中
-	PrisonDays int          `json:"prisonDays"` // 剩余监禁天数
-	JoinTime   time.Time    `json:"joinTime"`   // 加入游戏的时间
+	ID             string       `json:"id"`
+	Name           string       `json:"name"`
+	Coins          int          `json:"coins"`
+	Position       int          `json:"position"`
+	Status         PlayerStatus `json:"status"`
+	HasRolled      bool         `json:"hasRolled"`           // 是否已经掷过骰子
+	DoublesRolled  int          `json:"doublesRolled"`       // 本回合连续掷出对子的次数
+	InPrison       bool         `json:"inPrison"`            // 是否在监狱中
+	PrisonDays     int          `json:"prisonDays"`          // 剩余监禁天数
+	EscapeAttempts int          `json:"escapeAttempts"`      // 本次服刑已尝试越狱的次数
+	JoinTime       time.Time    `json:"joinTime"`            // 加入游戏的时间
+	Debts          []Debt       `json:"debts,omitempty"`     // 尚未偿还的债务，按产生的先后排列
+	HeldCards      []HeldCard   `json:"heldCards,omitempty"` // 持有的卡片，如出狱许可证
+	TurnsTaken     int          `json:"turnsTaken"`          // 已开始的回合数
+	AtBank         bool         `json:"atBank"`              // 本回合是否停留或经过了银行
+	Deposit        int          `json:"deposit"`             // 银行存款
+	Loan           *Loan        `json:"loan,omitempty"`      // 尚未还清的银行贷款
+}
+
+// Debt 玩家无力立即支付时产生的债务
+type Debt struct {
+	CreditorID string `json:"creditorId"` // 债权人，玩家ID或 AccountPrizePool
+	Amount     int    `json:"amount"`
+	Reason     string `json:"reason"`
 }
 
 // NewPlayer 创建新玩家
@@ -47,16 +62,11 @@ func (p *Player) CanMove() bool {
 	return !p.HasRolled && !p.InPrison && p.Status == PlayerStatusPlaying
 }
 
-// EnterPrison 进入监狱
-func (p *Player) EnterPrison() {
-	p.InPrison = true
-	p.PrisonDays = 1
-}
-
 // ExitPrison 离开监狱
 func (p *Player) ExitPrison() {
 	p.InPrison = false
 	p.PrisonDays = 0
+	p.EscapeAttempts = 0
 }
 
 // UpdateStatus 更新玩家状态
@@ -84,6 +94,16 @@ func (p *Player) MoveTo(position int) {
 	p.HasRolled = true
 }
 
+// removeHeldCard 移除一张持有的卡片
+func (p *Player) removeHeldCard(deck, cardID string) {
+	for i, card := range p.HeldCards {
+		if card.Deck == deck && card.CardID == cardID {
+			p.HeldCards = append(p.HeldCards[:i:i], p.HeldCards[i+1:]...)
+			return
+		}
+	}
+}
+
 // EndTurn 结束回合
 func (p *Player) EndTurn() {
 	p.HasRolled = false
@@ -92,50 +112,90 @@ func (p *Player) EndTurn() {
 // GetStatus 获取玩家状态视图
 func (p *Player) GetStatus() PlayerStatusView {
 	return PlayerStatusView{
-		ID:         p.ID,
-		Name:       p.Name,
-		Coins:      p.Coins,
-		Position:   p.Position,
-		Status:     p.Status,
-		InPrison:   p.InPrison,
-		PrisonDays: p.PrisonDays,
-		HasRolled:  p.HasRolled,
+		ID:             p.ID,
+		Name:           p.Name,
+		Coins:          p.Coins,
+		Position:       p.Position,
+		Status:         p.Status,
+		InPrison:       p.InPrison,
+		PrisonDays:     p.PrisonDays,
+		EscapeAttempts: p.EscapeAttempts,
+		HasRolled:      p.HasRolled,
+		Debts:          p.Debts,
+		HeldCards:      p.HeldCards,
+		AtBank:         p.AtBank,
+		Deposit:        p.Deposit,
+		Loan:           p.Loan,
 	}
 }
 
 // PlayerStatusView 玩家状态视图
 type PlayerStatusView struct {
-	ID         string       `json:"id"`
-	Name       string       `json:"name"`
-	Coins      int          `json:"coins"`
-	Position   int          `json:"position"`
-	Status     PlayerStatus `json:"status"`
-	InPrison   bool         `json:"inPrison"`
-	PrisonDays int          `json:"prisonDays"`
-	HasRolled  bool         `json:"hasRolled"`
+	ID             string       `json:"id"`
+	Name           string       `json:"name"`
+	Coins          int          `json:"coins"`
+	Position       int          `json:"position"`
+	Status         PlayerStatus `json:"status"`
+	InPrison       bool         `json:"inPrison"`
+	PrisonDays     int          `json:"prisonDays"`
+	EscapeAttempts int          `json:"escapeAttempts"`
+	HasRolled      bool         `json:"hasRolled"`
+	Debts          []Debt       `json:"debts,omitempty"`
+	HeldCards      []HeldCard   `json:"heldCards,omitempty"`
+	AtBank         bool         `json:"atBank"`
+	Deposit        int          `json:"deposit"`
+	Loan           *Loan        `json:"loan,omitempty"`
 }
 
 // Clone 创建玩家的深拷贝
 func (p *Player) Clone() *Player {
+	var loan *Loan
+	if p.Loan != nil {
+		l := *p.Loan
+		loan = &l
+	}
+
 	return &Player{
-		ID:         p.ID,
-		Name:       p.Name,
-		Coins:      p.Coins,
-		Position:   p.Position,
-		Status:     p.Status,
-		HasRolled:  p.HasRolled,
-		InPrison:   p.InPrison,
-		PrisonDays: p.PrisonDays,
-		JoinTime:   p.JoinTime,
+		ID:             p.ID,
+		Name:           p.Name,
+		Coins:          p.Coins,
+		Position:       p.Position,
+		Status:         p.Status,
+		HasRolled:      p.HasRolled,
+		DoublesRolled:  p.DoublesRolled,
+		InPrison:       p.InPrison,
+		PrisonDays:     p.PrisonDays,
+		EscapeAttempts: p.EscapeAttempts,
+		JoinTime:       p.JoinTime,
+		Debts:          append([]Debt(nil), p.Debts...),
+		HeldCards:      append([]HeldCard(nil), p.HeldCards...),
+		TurnsTaken:     p.TurnsTaken,
+		AtBank:         p.AtBank,
+		Deposit:        p.Deposit,
+		Loan:           loan,
+	}
+}
+
+// InDebt 检查玩家是否有未偿还的债务
+func (p *Player) InDebt() bool {
+	return len(p.Debts) > 0
+}
+
+// DebtTotal 获取所有未偿还债务的总额
+func (p *Player) DebtTotal() int {
+	total := 0
+	for _, debt := range p.Debts {
+		total += debt.Amount
 	}
+	return total
 }
 
-// IsBankrupt 检查玩家是否破产
+// IsBankrupt 检查玩家是否已破产出局
 func (p *Player) IsBankrupt() bool {
-	return p.Coins <= 0
+	return p.Status == PlayerStatusBankrupt
 }
 
 // IsActive 检查玩家是否处于活跃状态
 func (p *Player) IsActive() bool {
-	return p.Status == PlayerStatusPlaying && !p.IsBankrupt()
+	return p.Status == PlayerStatusPlaying
 }
@@ -0,0 +1,95 @@
+// internal/game/prison.go
+package game
+
+import (
+	"monopoly/pkg/utils"
+)
+
+// PayBail 支付保释金出狱，保释金进入奖池，出狱后可在本回合正常掷骰
+func (g *Game) PayBail(playerID string) (*GameAction, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	player, err := g.validatePrisonRelease(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if player.InDebt() {
+		return nil, utils.ErrOutstandingDebt
+	}
+
+	fee := g.Settings.BailFee
+	if player.Coins < fee {
+		return nil, utils.ErrInsufficientFunds
+	}
+
+	g.transferCoins(playerID, AccountPrizePool, fee, "bail")
+	g.emit(&PrisonReleased{PlayerID: playerID, Reason: "bail"})
+
+	action := &GameAction{
+		Type:      ActionBail,
+		PlayerID:  playerID,
+		Position:  player.Position,
+		Amount:    fee,
+		Timestamp: g.now(),
+	}
+	g.AddAction(action)
+	return action, nil
+}
+
+// UsePrisonCard 使用持有的出狱许可证出狱，出狱后可在本回合正常掷骰
+func (g *Game) UsePrisonCard(playerID string) (*GameAction, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	player, err := g.validatePrisonRelease(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	card, ok := g.findPrisonCard(player)
+	if !ok {
+		return nil, utils.ErrNoPrisonCard
+	}
+
+	g.emit(&CardUsed{PlayerID: playerID, Deck: card.Deck, CardID: card.CardID})
+	g.emit(&PrisonReleased{PlayerID: playerID, Reason: "card"})
+
+	action := &GameAction{
+		Type:      ActionPrisonCard,
+		PlayerID:  playerID,
+		Position:  player.Position,
+		CardID:    card.CardID,
+		Timestamp: g.now(),
+	}
+	g.AddAction(action)
+	return action, nil
+}
+
+// validatePrisonRelease 验证玩家在狱中且本回合尚未掷骰
+func (g *Game) validatePrisonRelease(playerID string) (*Player, error) {
+	if err := g.validateGameState(playerID); err != nil {
+		return nil, err
+	}
+
+	player := g.Players[playerID]
+	if !player.InPrison {
+		return nil, utils.ErrNotInPrison
+	}
+
+	if player.HasRolled {
+		return nil, utils.ErrAlreadyRolled
+	}
+	return player, nil
+}
+
+// findPrisonCard 查找玩家持有的出狱许可证
+func (g *Game) findPrisonCard(player *Player) (HeldCard, bool) {
+	for _, held := range player.HeldCards {
+		if card := g.Map.findCard(held.Deck, held.CardID); card != nil && card.Effect == CardGetOutOfPrison {
+			return held, true
+		}
+	}
+	return HeldCard{}, false
+}
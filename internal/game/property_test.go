@@ -0,0 +1,54 @@
+// internal/game/property_test.go
+package game
+
+import (
+	"errors"
+	"monopoly/pkg/utils"
+	"testing"
+)
+
+func TestPropertyActionsRejectOutOfRangePosition(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+	playerID := g.CurrentPlayerID
+	events := len(g.Events)
+
+	actions := map[string]func(position int) (*GameAction, error){
+		"upgrade":     func(position int) (*GameAction, error) { return g.UpgradeProperty(playerID, position) },
+		"sellUpgrade": func(position int) (*GameAction, error) { return g.SellUpgrade(playerID, position) },
+		"mortgage":    func(position int) (*GameAction, error) { return g.MortgageProperty(playerID, position) },
+		"unmortgage":  func(position int) (*GameAction, error) { return g.UnmortgageProperty(playerID, position) },
+	}
+	for name, act := range actions {
+		for _, position := range []int{-1, len(g.Map.Tiles), len(g.Map.Tiles) + 100} {
+			if action, err := act(position); !errors.Is(err, utils.ErrInvalidInput) {
+				t.Fatalf("%s at %d: got action %+v and error %v, want %v", name, position, action, err, utils.ErrInvalidInput)
+			}
+		}
+	}
+	if len(g.Events) != events {
+		t.Fatalf("rejected actions emitted %d events", len(g.Events)-events)
+	}
+}
+
+func TestUpgradePropertyRequiresFullGroup(t *testing.T) {
+	settings := DefaultSettings()
+	settings.RequireFullGroup = true
+	g := newTestGameWithSettings(t, settings, 1, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+	g.emit(&PropertyOwnerChanged{Position: 1, OwnerID: player.ID})
+
+	if _, err := g.UpgradeProperty(player.ID, 1); !errors.Is(err, utils.ErrGroupNotOwned) {
+		t.Fatalf("got error %v, want %v", err, utils.ErrGroupNotOwned)
+	}
+
+	g.emit(&PropertyOwnerChanged{Position: 2, OwnerID: player.ID})
+	coins := player.Coins
+	action, err := g.UpgradeProperty(player.ID, 1)
+	if err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	if g.Map.Tiles[1].Level != 1 || player.Coins != coins-action.Amount {
+		t.Fatalf("got level %d and coins %d, want level 1 and coins %d", g.Map.Tiles[1].Level, player.Coins, coins-action.Amount)
+	}
+	assertReplayMatches(t, g)
+}
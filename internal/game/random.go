@@ -0,0 +1,78 @@
+// internal/game/random.go
+package game
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Random 游戏专属的随机数源
+// 每局游戏持有独立的随机数源，相同的种子加相同的操作序列必然得到相同的结果
+type Random struct {
+	seed   int64
+	source *countingSource
+	rand   *rand.Rand
+}
+
+// countingSource 记录已生成随机数个数的随机源，用于恢复随机数源的位置
+type countingSource struct {
+	source rand.Source64
+	draws  uint64
+}
+
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.source.Int63()
+}
+
+func (s *countingSource) Uint64() uint64 {
+	s.draws++
+	return s.source.Uint64()
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.source.Seed(seed)
+	s.draws = 0
+}
+
+// NewRandom 根据种子创建随机数源
+func NewRandom(seed int64) *Random {
+	source := &countingSource{source: rand.NewSource(seed).(rand.Source64)}
+	return &Random{
+		seed:   seed,
+		source: source,
+		rand:   rand.New(source),
+	}
+}
+
+// NewSeed 生成一个新的随机种子
+func NewSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+// Seed 获取创建该随机数源时使用的种子
+func (r *Random) Seed() int64 {
+	return r.seed
+}
+
+// Draws 获取随机源已生成的随机数个数
+func (r *Random) Draws() uint64 {
+	return r.source.draws
+}
+
+// advance 将随机源推进到已生成 draws 个随机数的位置
+func (r *Random) advance(draws uint64) {
+	for r.source.draws < draws {
+		r.source.Uint64()
+	}
+}
+
+// Intn 返回 [0, n) 范围内的随机整数
+func (r *Random) Intn(n int) int {
+	return r.rand.Intn(n)
+}
+
+// Shuffle 随机打乱 n 个元素的顺序
+func (r *Random) Shuffle(n int, swap func(i, j int)) {
+	r.rand.Shuffle(n, swap)
+}
@@ -0,0 +1,81 @@
+// internal/game/random_test.go
+package game
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// playTurns 让每位当前玩家掷骰、尽量购买落脚的地产后结束回合，共进行 n 个回合，操作出错时忽略
+func playTurns(g *Game, n int) {
+	for i := 0; i < n && g.Status == StatusPlaying; i++ {
+		id := g.CurrentPlayerID
+		g.RollDice(id)
+		g.BuyProperty(id)
+		g.EndTurn(id)
+	}
+}
+
+// diceRolls 获取事件日志中的全部掷骰结果
+func diceRolls(g *Game) [][]int {
+	rolls := make([][]int, 0)
+	for _, event := range g.Events {
+		if rolled, ok := event.Payload.(*DiceRolled); ok {
+			rolls = append(rolls, rolled.Dice)
+		}
+	}
+	return rolls
+}
+
+func TestSameSeedProducesSameGame(t *testing.T) {
+	first := newTestGame(t, 7, "a", "b", "c")
+	second := newTestGame(t, 7, "a", "b", "c")
+	playTurns(first, 60)
+	playTurns(second, 60)
+
+	if count := countEvents(first, EventCardDrawn); count == 0 {
+		t.Fatal("no cards drawn, the test does not cover card draws")
+	}
+	want, err := json.Marshal(first)
+	if err != nil {
+		t.Fatalf("marshal game: %v", err)
+	}
+	got, err := json.Marshal(second)
+	if err != nil {
+		t.Fatalf("marshal game: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("games with the same seed differ:\n got %s\nwant %s", got, want)
+	}
+	assertReplayMatches(t, first)
+}
+
+func TestDifferentSeedsProduceDifferentRolls(t *testing.T) {
+	first := newTestGame(t, 1, "a", "b")
+	second := newTestGame(t, 2, "a", "b")
+	playTurns(first, 20)
+	playTurns(second, 20)
+
+	if reflect.DeepEqual(diceRolls(first), diceRolls(second)) {
+		t.Fatalf("seeds 1 and 2 rolled the same dice %v", diceRolls(first))
+	}
+}
+
+func TestRandomAdvanceRestoresPosition(t *testing.T) {
+	r := NewRandom(42)
+	for i := 0; i < 5; i++ {
+		r.Intn(6)
+	}
+	restored := NewRandom(42)
+	restored.advance(r.Draws())
+
+	for i := 0; i < 10; i++ {
+		if got, want := restored.Intn(100), r.Intn(100); got != want {
+			t.Fatalf("draw %d after advance: got %d, want %d", i, got, want)
+		}
+	}
+	if restored.Seed() != 42 {
+		t.Fatalf("got seed %d, want 42", restored.Seed())
+	}
+}
@@ -0,0 +1,40 @@
+// internal/game/replay.go
+package game
+
+import (
+	"monopoly/pkg/utils"
+)
+
+// Replay 按顺序重放事件，重建应用完第 upTo 个事件（含）后的游戏状态
+// 随机数源同时恢复到该事件之后的位置，重建得到的游戏使用系统时钟且没有观察者
+func Replay(events []*Event, upTo int) (*Game, error) {
+	if len(events) == 0 || events[0].Type != EventGameCreated {
+		return nil, utils.ErrInvalidInput
+	}
+
+	if upTo < 0 || upTo >= len(events) {
+		return nil, utils.ErrInvalidInput
+	}
+
+	g := newEmptyGame()
+	for _, event := range events[:upTo+1] {
+		if event.Index != len(g.Events) {
+			return nil, utils.ErrInvalidInput
+		}
+		event.Payload.apply(g, event)
+		g.appendEvent(event)
+	}
+	g.rng.advance(events[upTo].RandomDraws)
+
+	return g, nil
+}
+
+// Restore 从完整的事件日志恢复游戏，恢复后的游戏使用指定时钟，可以继续进行
+func Restore(events []*Event, clock Clock) (*Game, error) {
+	g, err := Replay(events, len(events)-1)
+	if err != nil {
+		return nil, err
+	}
+	g.clock = clock
+	return g, nil
+}
@@ -0,0 +1,68 @@
+// internal/game/replay_test.go
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"monopoly/pkg/utils"
+	"testing"
+)
+
+func TestReplayUpToMidGame(t *testing.T) {
+	g := newTestGame(t, 3, "a", "b", "c")
+	playTurns(g, 10)
+	upTo := len(g.Events) - 1
+	want, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("marshal game: %v", err)
+	}
+	playTurns(g, 10)
+
+	replayed, err := Replay(g.Events, upTo)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if len(replayed.Events) != upTo+1 {
+		t.Fatalf("got %d events, want %d", len(replayed.Events), upTo+1)
+	}
+	got, err := json.Marshal(replayed)
+	if err != nil {
+		t.Fatalf("marshal replayed game: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("replayed state differs from the state at event %d:\n got %s\nwant %s", upTo, got, want)
+	}
+
+	// 随机数源恢复到该事件之后的位置，继续进行时与原游戏抽取相同的随机数
+	if replayed.rng.Draws() != g.Events[upTo].RandomDraws {
+		t.Fatalf("got %d random draws, want %d", replayed.rng.Draws(), g.Events[upTo].RandomDraws)
+	}
+}
+
+func TestReplayUpToFirstEvent(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+
+	replayed, err := Replay(g.Events, 0)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if replayed.ID != g.ID || replayed.Seed != g.Seed || replayed.Status != StatusWaiting {
+		t.Fatalf("got game %s with seed %d and status %s, want the newly created game", replayed.ID, replayed.Seed, replayed.Status)
+	}
+	if len(replayed.Players) != 0 || len(replayed.Events) != 1 {
+		t.Fatalf("got %d players and %d events, want none and 1", len(replayed.Players), len(replayed.Events))
+	}
+}
+
+func TestReplayRejectsInvalidUpTo(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b")
+
+	for _, upTo := range []int{-1, len(g.Events), len(g.Events) + 10} {
+		if _, err := Replay(g.Events, upTo); !errors.Is(err, utils.ErrInvalidInput) {
+			t.Fatalf("upTo %d: got error %v, want %v", upTo, err, utils.ErrInvalidInput)
+		}
+	}
+	if _, err := Replay(g.Events[1:], 0); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("log without gameCreated: got error %v, want %v", err, utils.ErrInvalidInput)
+	}
+}
@@ -0,0 +1,295 @@
+// internal/game/settings.go
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"monopoly/pkg/utils"
+	"time"
+)
+
+// TurnOrderMode 座位顺序的决定方式
+type TurnOrderMode string
+
+const (
+	TurnOrderJoin    TurnOrderMode = "join"    // 按加入顺序
+	TurnOrderRandom  TurnOrderMode = "random"  // 使用游戏种子随机打乱
+	TurnOrderRollOff TurnOrderMode = "rollOff" // 开局掷骰，点数大者先行
+)
+
+// Duration 可序列化为 "30s" 形式的时间长度，反序列化时也接受以秒为单位的数字
+type Duration time.Duration
+
+// MarshalJSON 序列化为时间字符串
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON 解析时间字符串或秒数
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err == nil {
+		*d = Duration(seconds * float64(time.Second))
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// 规则预设名称
+const (
+	PresetQuick      = "quick"
+	PresetClassic    = "classic"
+	PresetHighStakes = "high-stakes"
+)
+
+// GameSettings 每局游戏的规则设置，创建游戏时指定，开始前主持人可以修改，开始后不再改变
+type GameSettings struct {
+	MaxPlayers   int      `json:"maxPlayers"`   // 最多玩家数
+	MinPlayers   int      `json:"minPlayers"`   // 开始游戏所需的最少玩家数
+	TurnTimeout  Duration `json:"turnTimeout"`  // 回合时长，超时自动结束回合
+	GameTimeout  Duration `json:"gameTimeout"`  // 游戏时长，超时自动结束游戏
+	LobbyTimeout Duration `json:"lobbyTimeout"` // 等待开始的时长，超时未开始的游戏被放弃并退还买入金额
+	PauseTimeout Duration `json:"pauseTimeout"` // 主持人暂停的最长时长，超时自动恢复游戏
+
+	TurnOrder       TurnOrderMode `json:"turnOrder"`
+	AuctionsEnabled bool          `json:"auctionsEnabled"` // 未购买的地产是否进入拍卖
+	AuctionDuration Duration      `json:"auctionDuration"` // 拍卖时长
+
+	BuyIn               int          `json:"buyIn"`               // 买入金额，加入时从用户钱包托管，作为玩家的初始金币
+	EntranceFee         int          `json:"entranceFee"`         // 入场费，开始游戏时进入奖池
+	PassingGoRewardRate float64      `json:"passingGoRewardRate"` // 经过起点的奖励占奖池的比例
+	PayoutScheme        PayoutScheme `json:"payoutScheme"`        // 游戏结束时的奖池分配方式
+	PrizeRatios         []float64    `json:"prizeRatios"`         // topN 和 rankShared 方式下各名次的分配比例
+
+	MonopolyRentMultiplier int     `json:"monopolyRentMultiplier"` // 拥有整组地产时的过路费倍数
+	RequireFullGroup       bool    `json:"requireFullGroup"`       // 升级是否要求拥有整组且无抵押
+	EvenBuild              bool    `json:"evenBuild"`              // 是否要求在组内均衡升级
+	UpgradeCostRate        float64 `json:"upgradeCostRate"`        // 每级升级费用占地产原价的比例
+	SellUpgradeRate        float64 `json:"sellUpgradeRate"`        // 出售一级升级退还的金额占地产原价的比例
+	MortgageRate           float64 `json:"mortgageRate"`           // 抵押可得金额占地产原价的比例
+	MortgageInterestRate   float64 `json:"mortgageInterestRate"`   // 赎回时额外支付的利息占抵押本金的比例
+
+	DiceCount       int `json:"diceCount"`       // 每次掷骰的骰子数量
+	DiceSides       int `json:"diceSides"`       // 骰子面数
+	SpeedingDoubles int `json:"speedingDoubles"` // 连续掷出多少次对子时入狱，0表示不限制
+
+	PrisonTurns       int `json:"prisonTurns"`       // 入狱后需要度过的回合数
+	BailFee           int `json:"bailFee"`           // 保释金，支付给奖池
+	MaxEscapeAttempts int `json:"maxEscapeAttempts"` // 服刑期间最多尝试掷出对子越狱的次数
+
+	MaxLoanAmount       int     `json:"maxLoanAmount"`       // 单笔贷款上限，0表示不提供贷款
+	LoanInterestRate    float64 `json:"loanInterestRate"`    // 贷款利息占本金的比例
+	LoanTermTurns       int     `json:"loanTermTurns"`       // 贷款在借款人的第几个回合后到期
+	DepositInterestRate float64 `json:"depositInterestRate"` // 存款每经过一次起点获得的利息比例
+}
+
+// DefaultSettings 获取默认规则设置，即 classic 预设
+func DefaultSettings() GameSettings {
+	return GameSettings{
+		MaxPlayers:   4,
+		MinPlayers:   2,
+		TurnTimeout:  Duration(30 * time.Second),
+		GameTimeout:  Duration(10 * time.Minute),
+		LobbyTimeout: Duration(30 * time.Minute),
+		PauseTimeout: Duration(10 * time.Minute),
+
+		TurnOrder:       TurnOrderJoin,
+		AuctionsEnabled: false,
+		AuctionDuration: Duration(15 * time.Second),
+
+		BuyIn:               5000,
+		EntranceFee:         1000,
+		PassingGoRewardRate: 0.02,
+		PayoutScheme:        PayoutTopN,
+		PrizeRatios:         []float64{0.5, 0.3, 0.15, 0.05},
+
+		MonopolyRentMultiplier: 2,
+		RequireFullGroup:       false,
+		EvenBuild:              false,
+		UpgradeCostRate:        0.5,
+		SellUpgradeRate:        0.25,
+		MortgageRate:           0.5,
+		MortgageInterestRate:   0.1,
+
+		DiceCount:       2,
+		DiceSides:       6,
+		SpeedingDoubles: 3,
+
+		PrisonTurns:       2,
+		BailFee:           100,
+		MaxEscapeAttempts: 2,
+
+		MaxLoanAmount:       2000,
+		LoanInterestRate:    0.1,
+		LoanTermTurns:       5,
+		DepositInterestRate: 0.05,
+	}
+}
+
+// PresetSettings 获取指定名称的规则预设，名称为空时返回 classic
+func PresetSettings(name string) (GameSettings, error) {
+	s := DefaultSettings()
+	switch name {
+	case "", PresetClassic:
+	case PresetQuick:
+		// 节奏更快：更短的回合和游戏时长，入场费更低，经过起点奖励更高
+		s.TurnTimeout = Duration(15 * time.Second)
+		s.GameTimeout = Duration(5 * time.Minute)
+		s.LobbyTimeout = Duration(10 * time.Minute)
+		s.AuctionDuration = Duration(10 * time.Second)
+		s.BuyIn = 2000
+		s.EntranceFee = 500
+		s.PassingGoRewardRate = 0.05
+		s.PayoutScheme = PayoutWinnerTakesAll
+		s.PrisonTurns = 1
+		s.MaxEscapeAttempts = 1
+		s.LoanTermTurns = 3
+	case PresetHighStakes:
+		// 高风险：入场费、过路费倍数和各项利息更高，奖池集中分给前两名
+		s.TurnTimeout = Duration(45 * time.Second)
+		s.GameTimeout = Duration(20 * time.Minute)
+		s.AuctionsEnabled = true
+		s.BuyIn = 10000
+		s.EntranceFee = 3000
+		s.PassingGoRewardRate = 0.01
+		s.PayoutScheme = PayoutRankShared
+		s.PrizeRatios = []float64{0.7, 0.3}
+		s.MonopolyRentMultiplier = 3
+		s.RequireFullGroup = true
+		s.MortgageRate = 0.4
+		s.MortgageInterestRate = 0.2
+		s.BailFee = 300
+		s.MaxLoanAmount = 5000
+		s.LoanInterestRate = 0.25
+	default:
+		return GameSettings{}, fmt.Errorf("%w: unknown preset %q", utils.ErrInvalidInput, name)
+	}
+	return s, nil
+}
+
+// Validate 验证规则设置
+func (s GameSettings) Validate() error {
+	if s.MaxPlayers < 2 || s.MaxPlayers > 8 {
+		return invalidSetting("maxPlayers")
+	}
+	if s.MinPlayers < 2 || s.MinPlayers > s.MaxPlayers {
+		return invalidSetting("minPlayers")
+	}
+	if s.TurnTimeout <= 0 {
+		return invalidSetting("turnTimeout")
+	}
+	if s.GameTimeout <= 0 {
+		return invalidSetting("gameTimeout")
+	}
+	if s.LobbyTimeout <= 0 {
+		return invalidSetting("lobbyTimeout")
+	}
+	if s.PauseTimeout <= 0 {
+		return invalidSetting("pauseTimeout")
+	}
+
+	switch s.TurnOrder {
+	case TurnOrderJoin, TurnOrderRandom, TurnOrderRollOff:
+	default:
+		return invalidSetting("turnOrder")
+	}
+
+	if s.AuctionsEnabled && s.AuctionDuration <= 0 {
+		return invalidSetting("auctionDuration")
+	}
+
+	if s.EntranceFee < 0 {
+		return invalidSetting("entranceFee")
+	}
+	if s.BuyIn <= 0 || s.BuyIn < s.EntranceFee {
+		return invalidSetting("buyIn")
+	}
+	if !validRate(s.PassingGoRewardRate) {
+		return invalidSetting("passingGoRewardRate")
+	}
+	if _, err := NewPayoutStrategy(s); err != nil {
+		return invalidSetting("payoutScheme")
+	}
+	if s.PayoutScheme == PayoutTopN || s.PayoutScheme == PayoutRankShared {
+		total := 0.0
+		for _, ratio := range s.PrizeRatios {
+			if !validRate(ratio) {
+				return invalidSetting("prizeRatios")
+			}
+			total += ratio
+		}
+		if total <= 0 || total > 1 {
+			return invalidSetting("prizeRatios")
+		}
+	}
+
+	if s.MonopolyRentMultiplier < 1 {
+		return invalidSetting("monopolyRentMultiplier")
+	}
+	if s.UpgradeCostRate <= 0 {
+		return invalidSetting("upgradeCostRate")
+	}
+	if s.SellUpgradeRate < 0 || s.SellUpgradeRate > s.UpgradeCostRate {
+		return invalidSetting("sellUpgradeRate")
+	}
+	if s.MortgageRate <= 0 || s.MortgageRate > 1 {
+		return invalidSetting("mortgageRate")
+	}
+	if s.MortgageInterestRate < 0 {
+		return invalidSetting("mortgageInterestRate")
+	}
+
+	if s.DiceCount < 1 || s.DiceCount > 5 {
+		return invalidSetting("diceCount")
+	}
+	if s.DiceSides < 2 || s.DiceSides > 100 {
+		return invalidSetting("diceSides")
+	}
+	if s.SpeedingDoubles < 0 {
+		return invalidSetting("speedingDoubles")
+	}
+
+	if s.PrisonTurns < 1 {
+		return invalidSetting("prisonTurns")
+	}
+	if s.BailFee < 0 {
+		return invalidSetting("bailFee")
+	}
+	if s.MaxEscapeAttempts < 0 || s.MaxEscapeAttempts > s.PrisonTurns {
+		return invalidSetting("maxEscapeAttempts")
+	}
+
+	if s.MaxLoanAmount < 0 {
+		return invalidSetting("maxLoanAmount")
+	}
+	if s.LoanInterestRate < 0 {
+		return invalidSetting("loanInterestRate")
+	}
+	if s.LoanTermTurns < 1 {
+		return invalidSetting("loanTermTurns")
+	}
+	if s.DepositInterestRate < 0 {
+		return invalidSetting("depositInterestRate")
+	}
+	return nil
+}
+
+// invalidSetting 生成指明无效字段的错误
+func invalidSetting(field string) error {
+	return fmt.Errorf("%w: invalid setting %q", utils.ErrInvalidInput, field)
+}
+
+// validRate 检查比例是否在 [0, 1] 范围内
+func validRate(rate float64) bool {
+	return rate >= 0 && rate <= 1
+}
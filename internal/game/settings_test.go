@@ -0,0 +1,115 @@
+// internal/game/settings_test.go
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"monopoly/pkg/utils"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPresetsAreValid(t *testing.T) {
+	for _, name := range []string{"", PresetClassic, PresetQuick, PresetHighStakes} {
+		s, err := PresetSettings(name)
+		if err != nil {
+			t.Fatalf("preset %q: %v", name, err)
+		}
+		if err := s.Validate(); err != nil {
+			t.Fatalf("preset %q is invalid: %v", name, err)
+		}
+	}
+
+	classic, _ := PresetSettings(PresetClassic)
+	if !reflect.DeepEqual(classic, DefaultSettings()) {
+		t.Fatalf("classic preset %+v differs from the defaults", classic)
+	}
+	quick, _ := PresetSettings(PresetQuick)
+	highStakes, _ := PresetSettings(PresetHighStakes)
+	if quick.BuyIn != 2000 || quick.PayoutScheme != PayoutWinnerTakesAll {
+		t.Fatalf("got quick preset %+v, want buy-in 2000 and winner takes all", quick)
+	}
+	if highStakes.BuyIn != 10000 || !highStakes.RequireFullGroup || !highStakes.AuctionsEnabled {
+		t.Fatalf("got high-stakes preset %+v, want buy-in 10000, full groups and auctions", highStakes)
+	}
+
+	if _, err := PresetSettings("turbo"); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("unknown preset: got error %v, want %v", err, utils.ErrInvalidInput)
+	}
+}
+
+func TestValidateRejectsInvalidSettings(t *testing.T) {
+	tests := []struct {
+		field  string
+		modify func(s *GameSettings)
+	}{
+		{"maxPlayers", func(s *GameSettings) { s.MaxPlayers = 9 }},
+		{"minPlayers", func(s *GameSettings) { s.MinPlayers = s.MaxPlayers + 1 }},
+		{"turnTimeout", func(s *GameSettings) { s.TurnTimeout = 0 }},
+		{"pauseTimeout", func(s *GameSettings) { s.PauseTimeout = 0 }},
+		{"turnOrder", func(s *GameSettings) { s.TurnOrder = "alphabetical" }},
+		{"auctionDuration", func(s *GameSettings) { s.AuctionsEnabled, s.AuctionDuration = true, 0 }},
+		{"buyIn", func(s *GameSettings) { s.BuyIn = s.EntranceFee - 1 }},
+		{"passingGoRewardRate", func(s *GameSettings) { s.PassingGoRewardRate = 1.5 }},
+		{"payoutScheme", func(s *GameSettings) { s.PayoutScheme = "lottery" }},
+		{"prizeRatios", func(s *GameSettings) { s.PrizeRatios = []float64{0.8, 0.3} }},
+		{"monopolyRentMultiplier", func(s *GameSettings) { s.MonopolyRentMultiplier = 0 }},
+		{"sellUpgradeRate", func(s *GameSettings) { s.SellUpgradeRate = s.UpgradeCostRate + 0.1 }},
+		{"mortgageRate", func(s *GameSettings) { s.MortgageRate = 0 }},
+		{"diceCount", func(s *GameSettings) { s.DiceCount = 0 }},
+		{"diceSides", func(s *GameSettings) { s.DiceSides = 1 }},
+		{"maxEscapeAttempts", func(s *GameSettings) { s.MaxEscapeAttempts = s.PrisonTurns + 1 }},
+		{"loanTermTurns", func(s *GameSettings) { s.LoanTermTurns = 0 }},
+		{"depositInterestRate", func(s *GameSettings) { s.DepositInterestRate = -0.1 }},
+	}
+
+	for _, tt := range tests {
+		s := DefaultSettings()
+		tt.modify(&s)
+		err := s.Validate()
+		if !errors.Is(err, utils.ErrInvalidInput) || !strings.Contains(err.Error(), `"`+tt.field+`"`) {
+			t.Fatalf("%s: got error %v, want invalid %q", tt.field, err, tt.field)
+		}
+	}
+}
+
+func TestSettingsDurationJSON(t *testing.T) {
+	var s GameSettings
+	if err := json.Unmarshal([]byte(`{"turnTimeout": "45s", "gameTimeout": 600}`), &s); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if time.Duration(s.TurnTimeout) != 45*time.Second || time.Duration(s.GameTimeout) != 10*time.Minute {
+		t.Fatalf("got timeouts %v and %v, want 45s and 10m", time.Duration(s.TurnTimeout), time.Duration(s.GameTimeout))
+	}
+
+	data, err := json.Marshal(DefaultSettings())
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"turnTimeout":"30s"`) {
+		t.Fatalf("got %s, want turnTimeout written as 30s", data)
+	}
+}
+
+func TestGameUsesItsSettings(t *testing.T) {
+	settings, _ := PresetSettings(PresetQuick)
+	settings.UpgradeCostRate = 1
+	g := newTestGameWithSettings(t, settings, 1, "a", "b")
+	player := g.Players[g.CurrentPlayerID]
+
+	if g.PrizePool != 2*settings.EntranceFee {
+		t.Fatalf("got prize pool %d, want %d from the quick entrance fee", g.PrizePool, 2*settings.EntranceFee)
+	}
+
+	// 升级费用按本局设置的比例计算
+	g.emit(&PropertyOwnerChanged{Position: 1, OwnerID: player.ID})
+	action, err := g.UpgradeProperty(player.ID, 1)
+	if err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	if action.Amount != g.Map.Tiles[1].Price {
+		t.Fatalf("got upgrade cost %d, want the full price %d", action.Amount, g.Map.Tiles[1].Price)
+	}
+}
@@ -14,6 +14,8 @@ type Tile struct {
 	OwnerID   string   `json:"ownerId,omitempty"`   // 所有者ID(仅地产类型)
 	Level     int      `json:"level,omitempty"`     // 升级等级(仅地产类型)
 	RentPrice []int    `json:"rentPrice,omitempty"` // 各等级过路费(仅地产类型)
+	Mortgaged bool     `json:"mortgaged,omitempty"` // 是否已抵押(仅地产类型)
+	Group     string   `json:"group,omitempty"`     // 所属地产组(仅地产类型)
 }
 
 // NewTile 创建新的地块
@@ -52,25 +54,38 @@ func (t *Tile) GetRent() (int, error) {
 		return 0, utils.ErrInvalidPropertyLevel
 	}
 
+	// 已抵押的地产不收取过路费
+	if t.Mortgaged {
+		return 0, nil
+	}
+
 	return t.RentPrice[t.Level], nil
 }
 
 // CanBeUpgraded 检查地产是否可以升级
 func (t *Tile) CanBeUpgraded() bool {
-	return t.Type == TileProperty && t.Level < len(t.RentPrice)-1
+	return t.Type == TileProperty && !t.Mortgaged && t.Level < len(t.RentPrice)-1
 }
 
-// GetUpgradeCost 获取升级费用
-func (t *Tile) GetUpgradeCost() (int, error) {
-	if t.Type != TileProperty {
-		return 0, utils.ErrNotProperty
-	}
+// GetMortgageValue 获取抵押可得金额
+func (t *Tile) GetMortgageValue(s GameSettings) int {
+	return int(float64(t.Price) * s.MortgageRate)
+}
 
-	if !t.CanBeUpgraded() {
-		return 0, utils.ErrMaxLevel
-	}
+// GetUnmortgageCost 获取赎回费用（本金 + 利息）
+func (t *Tile) GetUnmortgageCost(s GameSettings) int {
+	principal := t.GetMortgageValue(s)
+	return principal + int(float64(principal)*s.MortgageInterestRate)
+}
+
+// GetUpgradeCost 获取每级升级费用
+func (t *Tile) GetUpgradeCost(s GameSettings) int {
+	return int(float64(t.Price) * s.UpgradeCostRate)
+}
 
-	return t.Price / 2, nil
+// GetSellUpgradeRefund 获取出售一级升级退还的金额
+func (t *Tile) GetSellUpgradeRefund(s GameSettings) int {
+	return int(float64(t.Price) * s.SellUpgradeRate)
 }
 
 // Upgrade 升级地产
@@ -102,14 +117,20 @@ func (t *Tile) SetOwner(playerID string) error {
 }
 
 // GetValue 获取地产当前价值
-func (t *Tile) GetValue() (int, error) {
+func (t *Tile) GetValue(s GameSettings) (int, error) {
 	if t.Type != TileProperty {
 		return 0, utils.ErrNotProperty
 	}
 
 	// 地产价值 = 原价 + (升级等级 * 升级费用)
-	upgradeValue := t.Level * (t.Price / 2)
-	return t.Price + upgradeValue, nil
+	upgradeValue := t.Level * t.GetUpgradeCost(s)
+	value := t.Price + upgradeValue
+
+	// 已抵押的地产需扣除抵押本金
+	if t.Mortgaged {
+		value -= t.GetMortgageValue(s)
+	}
+	return value, nil
 }
 
 // IsSpecialTile 检查是否为特殊地块
@@ -131,6 +152,8 @@ func (t *Tile) Clone() *Tile {
 		OwnerID:   t.OwnerID,
 		Level:     t.Level,
 		RentPrice: rentPriceCopy,
+		Mortgaged: t.Mortgaged,
+		Group:     t.Group,
 	}
 }
 
@@ -138,4 +161,5 @@ func (t *Tile) Clone() *Tile {
 func (t *Tile) Reset() {
 	t.OwnerID = ""
 	t.Level = 0
+	t.Mortgaged = false
 }
@@ -0,0 +1,269 @@
+// internal/game/trade.go
+package game
+
+import (
+	"fmt"
+	"monopoly/pkg/utils"
+	"time"
+)
+
+// TradeStatus 交易状态
+type TradeStatus string
+
+const (
+	TradePending   TradeStatus = "pending"
+	TradeAccepted  TradeStatus = "accepted"
+	TradeRejected  TradeStatus = "rejected"
+	TradeCountered TradeStatus = "countered"
+	TradeExpired   TradeStatus = "expired"
+)
+
+// TradeTerms 交易条款，从发起方的角度描述
+type TradeTerms struct {
+	OfferTiles   []int `json:"offerTiles"`   // 发起方给出的地产位置
+	OfferCoins   int   `json:"offerCoins"`   // 发起方给出的金币
+	RequestTiles []int `json:"requestTiles"` // 发起方索取的地产位置
+	RequestCoins int   `json:"requestCoins"` // 发起方索取的金币
+}
+
+// TradeOffer 玩家之间的交易报价
+type TradeOffer struct {
+	ID     string `json:"id"`
+	FromID string `json:"fromId"`
+	ToID   string `json:"toId"`
+	TradeTerms
+	Status    TradeStatus `json:"status"`
+	CounterOf string      `json:"counterOf,omitempty"` // 被还价的原交易ID
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+// Clone 创建交易报价的深拷贝
+func (t *TradeOffer) Clone() *TradeOffer {
+	clone := *t
+	clone.OfferTiles = append([]int(nil), t.OfferTiles...)
+	clone.RequestTiles = append([]int(nil), t.RequestTiles...)
+	return &clone
+}
+
+// ProposeTrade 向其他玩家发起交易，报价在当前回合结束时过期
+func (g *Game) ProposeTrade(fromID, toID string, terms TradeTerms) (*TradeOffer, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if err := g.validateTrade(fromID, toID, terms); err != nil {
+		return nil, err
+	}
+
+	offer := g.proposeTrade(fromID, toID, terms, "")
+	return offer.Clone(), nil
+}
+
+// AcceptTrade 接受交易，资产在游戏锁内一次性完成交换
+func (g *Game) AcceptTrade(playerID, tradeID string) (*TradeOffer, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	offer, err := g.findPendingTrade(playerID, tradeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.validateTrade(offer.FromID, offer.ToID, offer.TradeTerms); err != nil {
+		return nil, err
+	}
+
+	for _, position := range offer.OfferTiles {
+		g.emit(&PropertyOwnerChanged{Position: position, OwnerID: offer.ToID})
+	}
+	for _, position := range offer.RequestTiles {
+		g.emit(&PropertyOwnerChanged{Position: position, OwnerID: offer.FromID})
+	}
+	g.transferCoins(offer.FromID, offer.ToID, offer.OfferCoins, "trade")
+	g.transferCoins(offer.ToID, offer.FromID, offer.RequestCoins, "trade")
+	g.emit(&TradeStatusChanged{TradeID: offer.ID, Status: TradeAccepted})
+
+	g.recordTradeAction(ActionTradeAccept, playerID, offer)
+	return offer.Clone(), nil
+}
+
+// RejectTrade 拒绝交易
+func (g *Game) RejectTrade(playerID, tradeID string) (*TradeOffer, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	offer, err := g.findPendingTrade(playerID, tradeID)
+	if err != nil {
+		return nil, err
+	}
+
+	g.emit(&TradeStatusChanged{TradeID: offer.ID, Status: TradeRejected})
+	g.recordTradeAction(ActionTradeReject, playerID, offer)
+	return offer.Clone(), nil
+}
+
+// CounterTrade 对交易还价，原交易标记为已还价并由接收方发起新的报价
+func (g *Game) CounterTrade(playerID, tradeID string, terms TradeTerms) (*TradeOffer, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	offer, err := g.findPendingTrade(playerID, tradeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.validateTrade(playerID, offer.FromID, terms); err != nil {
+		return nil, err
+	}
+
+	g.emit(&TradeStatusChanged{TradeID: offer.ID, Status: TradeCountered})
+	counter := g.proposeTrade(playerID, offer.FromID, terms, offer.ID)
+	return counter.Clone(), nil
+}
+
+// GetTrades 获取所有交易报价
+func (g *Game) GetTrades() []*TradeOffer {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	trades := make([]*TradeOffer, len(g.Trades))
+	for i, offer := range g.Trades {
+		trades[i] = offer.Clone()
+	}
+	return trades
+}
+
+// proposeTrade 创建交易报价，调用方需已完成验证
+func (g *Game) proposeTrade(fromID, toID string, terms TradeTerms, counterOf string) *TradeOffer {
+	offer := &TradeOffer{
+		ID:         fmt.Sprintf("trade-%d", len(g.Trades)+1),
+		FromID:     fromID,
+		ToID:       toID,
+		TradeTerms: terms,
+		Status:     TradePending,
+		CounterOf:  counterOf,
+		CreatedAt:  g.now(),
+	}
+	g.emit(&TradeProposed{Offer: offer})
+
+	actionType := ActionTradePropose
+	if counterOf != "" {
+		actionType = ActionTradeCounter
+	}
+	g.recordTradeAction(actionType, fromID, offer)
+	return g.Trades[len(g.Trades)-1]
+}
+
+// expirePendingTrades 使所有待处理的报价过期，在回合结束时调用
+func (g *Game) expirePendingTrades() {
+	for _, offer := range g.Trades {
+		if offer.Status == TradePending {
+			g.emit(&TradeStatusChanged{TradeID: offer.ID, Status: TradeExpired})
+		}
+	}
+}
+
+// findPendingTrade 查找发给指定玩家且仍待处理的报价，报价不是发给该玩家时返回 ErrForbidden
+func (g *Game) findPendingTrade(playerID, tradeID string) (*TradeOffer, error) {
+	if g.Status != StatusPlaying {
+		return nil, utils.ErrInvalidGameState
+	}
+
+	for _, offer := range g.Trades {
+		if offer.ID != tradeID {
+			continue
+		}
+		if offer.ToID != playerID {
+			return nil, utils.ErrForbidden
+		}
+		if offer.Status != TradePending {
+			return nil, utils.ErrTradeNotPending
+		}
+		return offer, nil
+	}
+
+	return nil, utils.ErrTradeNotFound
+}
+
+// validateTrade 验证交易双方及条款：地产归属正确且无升级，双方金币充足，
+// 欠债的一方只能进行使自己净得金币的交易，避免在破产前把资产转给其他玩家
+func (g *Game) validateTrade(fromID, toID string, terms TradeTerms) error {
+	if g.Status != StatusPlaying {
+		return utils.ErrInvalidGameState
+	}
+
+	from, to := g.Players[fromID], g.Players[toID]
+	if from == nil || to == nil {
+		return utils.ErrPlayerNotFound
+	}
+
+	if fromID == toID {
+		return utils.ErrInvalidInput
+	}
+
+	if !from.IsActive() || !to.IsActive() {
+		return utils.ErrPlayerBankrupt
+	}
+
+	if terms.OfferCoins < 0 || terms.RequestCoins < 0 {
+		return utils.ErrInvalidInput
+	}
+
+	if len(terms.OfferTiles) == 0 && len(terms.RequestTiles) == 0 &&
+		terms.OfferCoins == 0 && terms.RequestCoins == 0 {
+		return utils.ErrInvalidInput
+	}
+
+	seen := make(map[int]bool)
+	for _, position := range terms.OfferTiles {
+		if err := g.validateTradeTile(position, fromID, seen); err != nil {
+			return err
+		}
+	}
+	for _, position := range terms.RequestTiles {
+		if err := g.validateTradeTile(position, toID, seen); err != nil {
+			return err
+		}
+	}
+
+	if from.Coins < terms.OfferCoins || to.Coins < terms.RequestCoins {
+		return utils.ErrInsufficientFunds
+	}
+
+	if from.InDebt() && terms.RequestCoins <= terms.OfferCoins {
+		return utils.ErrOutstandingDebt
+	}
+	if to.InDebt() && terms.OfferCoins <= terms.RequestCoins {
+		return utils.ErrOutstandingDebt
+	}
+
+	return nil
+}
+
+// validateTradeTile 验证交易中的单块地产
+func (g *Game) validateTradeTile(position int, ownerID string, seen map[int]bool) error {
+	if seen[position] {
+		return utils.ErrInvalidInput
+	}
+	seen[position] = true
+
+	tile, err := g.validateOwnedProperty(ownerID, position)
+	if err != nil {
+		return err
+	}
+
+	if tile.Level > 0 {
+		return utils.ErrPropertyHasUpgrades
+	}
+
+	return nil
+}
+
+// recordTradeAction 记录交易相关动作
+func (g *Game) recordTradeAction(actionType ActionType, playerID string, offer *TradeOffer) {
+	g.AddAction(&GameAction{
+		Type:      actionType,
+		PlayerID:  playerID,
+		TradeID:   offer.ID,
+		Timestamp: g.now(),
+	})
+}
@@ -0,0 +1,209 @@
+// internal/game/trade_test.go
+package game
+
+import (
+	"errors"
+	"monopoly/pkg/utils"
+	"testing"
+)
+
+func TestTradeRejectsDebtorGivingAwayAssets(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b", "c")
+	debtor := g.Players[g.CurrentPlayerID]
+	other := otherPlayer(g)
+	g.emit(&PropertyOwnerChanged{Position: 1, OwnerID: debtor.ID})
+	g.incurDebt(debtor, AccountPrizePool, debtor.Coins+100, "card:chance-fine")
+
+	giveaways := []TradeTerms{
+		{OfferCoins: debtor.Coins},
+		{OfferTiles: []int{1}},
+		{OfferTiles: []int{1}, OfferCoins: 100, RequestCoins: 100},
+	}
+	for _, terms := range giveaways {
+		if _, err := g.ProposeTrade(debtor.ID, other.ID, terms); !errors.Is(err, utils.ErrOutstandingDebt) {
+			t.Fatalf("terms %+v: got error %v, want %v", terms, err, utils.ErrOutstandingDebt)
+		}
+	}
+
+	// 对方发起的报价同样不能让欠债方净流出金币
+	offer, err := g.ProposeTrade(other.ID, debtor.ID, TradeTerms{RequestTiles: []int{1}})
+	if !errors.Is(err, utils.ErrOutstandingDebt) {
+		t.Fatalf("got offer %+v and error %v, want %v", offer, err, utils.ErrOutstandingDebt)
+	}
+
+	// 出售地产换取金币可以帮助欠债方还债
+	offer, err = g.ProposeTrade(debtor.ID, other.ID, TradeTerms{OfferTiles: []int{1}, RequestCoins: 200})
+	if err != nil {
+		t.Fatalf("propose sale: %v", err)
+	}
+	coins := debtor.Coins
+	if _, err := g.AcceptTrade(other.ID, offer.ID); err != nil {
+		t.Fatalf("accept sale: %v", err)
+	}
+	if debtor.Coins != coins+200 || g.Map.Tiles[1].OwnerID != other.ID {
+		t.Fatalf("sale not applied: coins %d, owner %q", debtor.Coins, g.Map.Tiles[1].OwnerID)
+	}
+	assertReplayMatches(t, g)
+}
+
+// tradeStatus 获取交易的当前状态
+func tradeStatus(g *Game, tradeID string) TradeStatus {
+	for _, offer := range g.Trades {
+		if offer.ID == tradeID {
+			return offer.Status
+		}
+	}
+	return ""
+}
+
+func TestAcceptTradeSwapsAssets(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b", "c")
+	from := g.Players[g.CurrentPlayerID]
+	to := otherPlayer(g)
+	g.emit(&PropertyOwnerChanged{Position: 1, OwnerID: from.ID})
+	g.emit(&PropertyOwnerChanged{Position: 4, OwnerID: to.ID})
+	fromCoins, toCoins := from.Coins, to.Coins
+
+	offer, err := g.ProposeTrade(from.ID, to.ID, TradeTerms{OfferTiles: []int{1}, OfferCoins: 300, RequestTiles: []int{4}, RequestCoins: 100})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+
+	// 只有报价的接收方可以处理报价
+	for _, id := range g.TurnOrder {
+		if id == to.ID {
+			continue
+		}
+		if _, err := g.AcceptTrade(id, offer.ID); !errors.Is(err, utils.ErrForbidden) {
+			t.Fatalf("accept by %s: got error %v, want %v", id, err, utils.ErrForbidden)
+		}
+	}
+	if _, err := g.AcceptTrade(to.ID, "trade-missing"); !errors.Is(err, utils.ErrTradeNotFound) {
+		t.Fatalf("accept unknown trade: got error %v, want %v", err, utils.ErrTradeNotFound)
+	}
+
+	if _, err := g.AcceptTrade(to.ID, offer.ID); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	if g.Map.Tiles[1].OwnerID != to.ID || g.Map.Tiles[4].OwnerID != from.ID {
+		t.Fatalf("got owners %q and %q, want tiles swapped", g.Map.Tiles[1].OwnerID, g.Map.Tiles[4].OwnerID)
+	}
+	if from.Coins != fromCoins-200 || to.Coins != toCoins+200 {
+		t.Fatalf("got coins %d/%d, want %d/%d", from.Coins, to.Coins, fromCoins-200, toCoins+200)
+	}
+	if status := tradeStatus(g, offer.ID); status != TradeAccepted {
+		t.Fatalf("got status %s, want %s", status, TradeAccepted)
+	}
+	if action := findAction(g, ActionTradeAccept); action == nil || action.TradeID != offer.ID || action.PlayerID != to.ID {
+		t.Fatalf("got accept action %+v, want one for %s by %s", action, offer.ID, to.ID)
+	}
+	if _, err := g.AcceptTrade(to.ID, offer.ID); !errors.Is(err, utils.ErrTradeNotPending) {
+		t.Fatalf("accept twice: got error %v, want %v", err, utils.ErrTradeNotPending)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestAcceptTradeRevalidatesAssets(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b", "c")
+	from := g.Players[g.CurrentPlayerID]
+	to := otherPlayer(g)
+	g.emit(&PropertyOwnerChanged{Position: 1, OwnerID: from.ID})
+
+	tileOffer, err := g.ProposeTrade(from.ID, to.ID, TradeTerms{OfferTiles: []int{1}, RequestCoins: 500})
+	if err != nil {
+		t.Fatalf("propose tile trade: %v", err)
+	}
+	coinOffer, err := g.ProposeTrade(from.ID, to.ID, TradeTerms{OfferCoins: 1000})
+	if err != nil {
+		t.Fatalf("propose coin trade: %v", err)
+	}
+
+	// 报价发出后地产被升级、金币被花掉，接受时重新验证
+	g.emit(&PropertyLevelChanged{Position: 1, Level: 1})
+	g.transferCoins(from.ID, AccountPrizePool, from.Coins-500, "test")
+	fromCoins, toCoins := from.Coins, to.Coins
+
+	if _, err := g.AcceptTrade(to.ID, tileOffer.ID); !errors.Is(err, utils.ErrPropertyHasUpgrades) {
+		t.Fatalf("accept upgraded tile: got error %v, want %v", err, utils.ErrPropertyHasUpgrades)
+	}
+	if _, err := g.AcceptTrade(to.ID, coinOffer.ID); !errors.Is(err, utils.ErrInsufficientFunds) {
+		t.Fatalf("accept spent coins: got error %v, want %v", err, utils.ErrInsufficientFunds)
+	}
+	if g.Map.Tiles[1].OwnerID != from.ID || from.Coins != fromCoins || to.Coins != toCoins {
+		t.Fatalf("rejected accept changed assets: owner %q, coins %d/%d", g.Map.Tiles[1].OwnerID, from.Coins, to.Coins)
+	}
+	if tradeStatus(g, tileOffer.ID) != TradePending || tradeStatus(g, coinOffer.ID) != TradePending {
+		t.Fatal("rejected accept changed the offer status")
+	}
+}
+
+func TestCounterTrade(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b", "c")
+	from := g.Players[g.CurrentPlayerID]
+	to := otherPlayer(g)
+	g.emit(&PropertyOwnerChanged{Position: 1, OwnerID: from.ID})
+
+	offer, err := g.ProposeTrade(from.ID, to.ID, TradeTerms{OfferTiles: []int{1}, RequestCoins: 800})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	if _, err := g.CounterTrade(from.ID, offer.ID, TradeTerms{OfferCoins: 500}); !errors.Is(err, utils.ErrForbidden) {
+		t.Fatalf("counter own offer: got error %v, want %v", err, utils.ErrForbidden)
+	}
+
+	// 还价从接收方的角度描述条款，原报价不能再被接受
+	counter, err := g.CounterTrade(to.ID, offer.ID, TradeTerms{OfferCoins: 500, RequestTiles: []int{1}})
+	if err != nil {
+		t.Fatalf("counter: %v", err)
+	}
+	if counter.FromID != to.ID || counter.ToID != from.ID || counter.CounterOf != offer.ID || counter.Status != TradePending {
+		t.Fatalf("got counter %+v, want a pending offer from %s to %s", counter, to.ID, from.ID)
+	}
+	if status := tradeStatus(g, offer.ID); status != TradeCountered {
+		t.Fatalf("got original status %s, want %s", status, TradeCountered)
+	}
+	if _, err := g.AcceptTrade(to.ID, offer.ID); !errors.Is(err, utils.ErrTradeNotPending) {
+		t.Fatalf("accept countered offer: got error %v, want %v", err, utils.ErrTradeNotPending)
+	}
+
+	fromCoins := from.Coins
+	if _, err := g.AcceptTrade(from.ID, counter.ID); err != nil {
+		t.Fatalf("accept counter: %v", err)
+	}
+	if g.Map.Tiles[1].OwnerID != to.ID || from.Coins != fromCoins+500 {
+		t.Fatalf("counter not applied: owner %q, coins %d", g.Map.Tiles[1].OwnerID, from.Coins)
+	}
+	assertReplayMatches(t, g)
+}
+
+func TestPendingTradesExpireAtEndOfTurn(t *testing.T) {
+	g := newTestGame(t, 1, "a", "b", "c")
+	from := g.Players[g.CurrentPlayerID]
+	to := otherPlayer(g)
+
+	offer, err := g.ProposeTrade(from.ID, to.ID, TradeTerms{OfferCoins: 100})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	rejected, err := g.ProposeTrade(from.ID, to.ID, TradeTerms{OfferCoins: 200})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	if _, err := g.RejectTrade(to.ID, rejected.ID); err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+
+	if err := g.EndTurn(from.ID); err != nil {
+		t.Fatalf("end turn: %v", err)
+	}
+	if status := tradeStatus(g, offer.ID); status != TradeExpired {
+		t.Fatalf("got status %s, want %s", status, TradeExpired)
+	}
+	if status := tradeStatus(g, rejected.ID); status != TradeRejected {
+		t.Fatalf("rejected offer changed to %s", status)
+	}
+	if _, err := g.AcceptTrade(to.ID, offer.ID); !errors.Is(err, utils.ErrTradeNotPending) {
+		t.Fatalf("accept expired offer: got error %v, want %v", err, utils.ErrTradeNotPending)
+	}
+	assertReplayMatches(t, g)
+}
@@ -0,0 +1,73 @@
+// internal/game/turnorder.go
+package game
+
+import "sort"
+
+// decideTurnOrder 根据规则设置决定座位顺序，调用方需持有游戏锁
+func (g *Game) decideTurnOrder() {
+	order := make([]string, len(g.TurnOrder))
+	copy(order, g.TurnOrder)
+
+	var rolls map[string][]int
+	switch g.Settings.TurnOrder {
+	case TurnOrderRandom:
+		g.rng.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+	case TurnOrderRollOff:
+		rolls = make(map[string][]int, len(order))
+		order = g.rollOff(order, rolls)
+	}
+
+	g.emit(&TurnOrderDecided{Order: order, Rolls: rolls})
+}
+
+// rollOff 开局掷骰决定顺序，点数相同的玩家重新掷骰决定彼此的先后
+func (g *Game) rollOff(playerIDs []string, rolls map[string][]int) []string {
+	if len(playerIDs) <= 1 {
+		return playerIDs
+	}
+
+	groups := make(map[int][]string)
+	for _, id := range playerIDs {
+		roll := g.rollDice(1)[0]
+		rolls[id] = append(rolls[id], roll)
+		groups[roll] = append(groups[roll], id)
+	}
+
+	values := make([]int, 0, len(groups))
+	for roll := range groups {
+		values = append(values, roll)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(values)))
+
+	order := make([]string, 0, len(playerIDs))
+	for _, roll := range values {
+		order = append(order, g.rollOff(groups[roll], rolls)...)
+	}
+	return order
+}
+
+// getOrderedPlayerIDs 获取座位顺序
+func (g *Game) getOrderedPlayerIDs() []string {
+	return g.TurnOrder
+}
+
+// getNextPlayerID 获取下一个仍在游戏中的玩家ID，已破产的玩家被跳过
+func (g *Game) getNextPlayerID(playerIDs []string) string {
+	start := 0
+	for i, id := range playerIDs {
+		if id == g.CurrentPlayerID {
+			start = i + 1
+			break
+		}
+	}
+
+	for offset := 0; offset < len(playerIDs); offset++ {
+		id := playerIDs[(start+offset)%len(playerIDs)]
+		if player := g.Players[id]; player != nil && player.IsActive() {
+			return id
+		}
+	}
+	return playerIDs[0] // 没有活跃玩家（不应该发生），返回第一个玩家
+}
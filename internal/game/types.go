@@ -1,59 +1,80 @@
 // internal/game/types.go
 package game
 
-import "time"
+// GameStatus 游戏状态
+type GameStatus string
 
-// 游戏相关常量
 const (
-	MaxPlayers      = 4
-	MinStartPlayers = 2
-	TurnTimeout     = 30 * time.Second
-	GameTimeout     = 10 * time.Minute
+	StatusWaiting   GameStatus = "waiting"
+	StatusPlaying   GameStatus = "playing"
+	StatusFinished  GameStatus = "finished"
+	StatusAbandoned GameStatus = "abandoned" // 超时未开始而被放弃
+	StatusPaused    GameStatus = "paused"    // 暂停，计时停止，暂停原因见 PauseReason
 )
 
-// GameStatus 游戏状态
-type GameStatus string
+// PauseReason 游戏暂停的原因
+type PauseReason string
 
 const (
-	StatusWaiting  GameStatus = "waiting"
-	StatusPlaying  GameStatus = "playing"
-	StatusFinished GameStatus = "finished"
+	PauseShutdown PauseReason = "shutdown" // 服务器停机，重启后自动恢复
+	PauseHost     PauseReason = "host"     // 主持人或管理员暂停，需手动恢复，超过 pauseTimeout 自动恢复
 )
 
 // PlayerStatus 玩家状态
 type PlayerStatus string
 
 const (
-	PlayerStatusWaiting PlayerStatus = "waiting"
-	PlayerStatusPlaying PlayerStatus = "playing"
-	PlayerStatusOffline PlayerStatus = "offline"
+	PlayerStatusWaiting  PlayerStatus = "waiting"
+	PlayerStatusPlaying  PlayerStatus = "playing"
+	PlayerStatusOffline  PlayerStatus = "offline"
+	PlayerStatusBankrupt PlayerStatus = "bankrupt" // 破产出局
 )
 
 // ActionType 动作类型
 type ActionType string
 
 const (
-	ActionRollDice    ActionType = "rollDice"
-	ActionBuyProperty ActionType = "buyProperty"
-	ActionPayRent     ActionType = "payRent"
-	ActionUpgrade     ActionType = "upgrade"
+	ActionRollDice     ActionType = "rollDice"
+	ActionSpeeding     ActionType = "speeding" // 连续掷出过多对子，直接入狱
+	ActionBuyProperty  ActionType = "buyProperty"
+	ActionPayRent      ActionType = "payRent"
+	ActionUpgrade      ActionType = "upgrade"
+	ActionSellUpgrade  ActionType = "sellUpgrade"
+	ActionMortgage     ActionType = "mortgage"
+	ActionUnmortgage   ActionType = "unmortgage"
+	ActionPayDebt      ActionType = "payDebt"
+	ActionBankrupt     ActionType = "bankrupt"
+	ActionTradePropose ActionType = "tradePropose"
+	ActionTradeAccept  ActionType = "tradeAccept"
+	ActionTradeReject  ActionType = "tradeReject"
+	ActionTradeCounter ActionType = "tradeCounter"
+	ActionDrawCard     ActionType = "drawCard" // 抽卡，CardID 为抽到的卡片
+	ActionPrison       ActionType = "prison"
+	ActionEscapeRoll   ActionType = "escapeRoll" // 在狱中掷骰尝试越狱，Amount 为1表示成功
+	ActionPrisonTurn   ActionType = "prisonTurn" // 越狱次数已用完，在狱中度过一个回合
+	ActionBail         ActionType = "bail"
+	ActionPrisonCard   ActionType = "prisonCard" // 使用出狱许可证
+	ActionLoan         ActionType = "loan"
+	ActionRepayLoan    ActionType = "repayLoan"
+	ActionDeposit      ActionType = "deposit"
+	ActionWithdraw     ActionType = "withdraw"
+	ActionAuction      ActionType = "auction"      // 拍卖结果，PlayerID 为空表示流拍
+	ActionTurnTimeout  ActionType = "turnTimeout"  // 回合超时，自动结束回合
+	ActionGameTimeout  ActionType = "gameTimeout"  // 游戏超时，自动结束游戏
+	ActionPauseTimeout ActionType = "pauseTimeout" // 主持人暂停超时，自动恢复游戏
+	ActionForceEnd     ActionType = "forceEnd"     // 管理员强制结束游戏
+	ActionKick         ActionType = "kick"         // 管理员将玩家踢出游戏，玩家按破产处理
 )
 
 // TileType 地块类型
 type TileType string
 
 const (
-	TileStart    TileType = "start"
-	TileProperty TileType = "property"
-	TileChance   TileType = "chance"
-	TileFate     TileType = "fate"
-	TileBank     TileType = "bank"
-	TilePrison   TileType = "prison"
-)
-
-// 游戏配置常量
-const (
-	InitialEntranceFee  = 1000
-	PassingGoRewardRate = 0.02 // 过路奖励为奖池的2%
-	ChanceRewardRate    = 0.01 // 机会奖励为奖池的1%
+	TileStart      TileType = "start"
+	TileProperty   TileType = "property"
+	TileChance     TileType = "chance"
+	TileFate       TileType = "fate"
+	TileBank       TileType = "bank"
+	TilePrison     TileType = "prison"     // 监狱（路过探监）
+	TileGoToPrison TileType = "goToPrison" // 入狱，停留时被送进监狱
 )
@@ -0,0 +1,123 @@
+// internal/ledger/check.go
+package ledger
+
+import (
+	"sort"
+)
+
+// Discrepancy 余额异常的账户
+type Discrepancy struct {
+	Account Account `json:"account"`
+	Ledger  int     `json:"ledger"` // 账本余额
+	Actual  int     `json:"actual"` // 实际余额
+	Detail  string  `json:"detail"`
+}
+
+// Report 账本一致性检查结果
+type Report struct {
+	Entries       int           `json:"entries"`
+	Issued        int           `json:"issued"`   // 从外部流入系统的金币净额
+	Balanced      bool          `json:"balanced"` // 所有账户余额之和为0，即没有金币凭空产生或消失
+	Discrepancies []Discrepancy `json:"discrepancies"`
+}
+
+// Consistent 账本是否平衡且与实际余额一致
+func (r *Report) Consistent() bool {
+	return r.Balanced && len(r.Discrepancies) == 0
+}
+
+// Verify 从头累加全部分录，验证每笔分录借贷相等、累计余额与缓存一致、
+// 所有账户余额之和为0，且除外部账户外没有负余额
+func (l *Ledger) Verify() *Report {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	report := &Report{
+		Entries:       len(l.entries),
+		Discrepancies: make([]Discrepancy, 0),
+	}
+
+	balances := make(map[Account]int)
+	for _, entry := range l.entries {
+		if entry.Amount <= 0 || entry.Debit == entry.Credit {
+			report.Discrepancies = append(report.Discrepancies, Discrepancy{
+				Account: entry.Debit,
+				Detail:  "invalid entry",
+			})
+			continue
+		}
+		balances[entry.Credit] -= entry.Amount
+		balances[entry.Debit] += entry.Amount
+	}
+
+	total := 0
+	for _, account := range sortedAccounts(balances, l.balances) {
+		balance := balances[account]
+		total += balance
+
+		if cached := l.balances[account]; cached != balance {
+			report.Discrepancies = append(report.Discrepancies, Discrepancy{
+				Account: account,
+				Ledger:  cached,
+				Actual:  balance,
+				Detail:  "cached balance differs from entries",
+			})
+		}
+		if account != AccountExternal && balance < 0 {
+			report.Discrepancies = append(report.Discrepancies, Discrepancy{
+				Account: account,
+				Ledger:  balance,
+				Detail:  "negative balance",
+			})
+		}
+	}
+
+	report.Issued = -balances[AccountExternal]
+	report.Balanced = total == 0
+	return report
+}
+
+// Reconcile 将 actual 中各账户的实际余额与账本余额比对，返回不一致的账户
+// 账本余额只累加 include 返回 true 的分录，include 为 nil 时累加全部分录
+func (l *Ledger) Reconcile(actual map[Account]int, include func(Entry) bool) []Discrepancy {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	balances := make(map[Account]int, len(actual))
+	for _, entry := range l.entries {
+		if include != nil && !include(entry) {
+			continue
+		}
+		balances[entry.Credit] -= entry.Amount
+		balances[entry.Debit] += entry.Amount
+	}
+
+	discrepancies := make([]Discrepancy, 0)
+	for _, account := range sortedAccounts(actual) {
+		if balances[account] != actual[account] {
+			discrepancies = append(discrepancies, Discrepancy{
+				Account: account,
+				Ledger:  balances[account],
+				Actual:  actual[account],
+				Detail:  "ledger balance differs from actual balance",
+			})
+		}
+	}
+	return discrepancies
+}
+
+// sortedAccounts 合并多个余额表的账户并排序，使检查结果稳定
+func sortedAccounts(tables ...map[Account]int) []Account {
+	seen := make(map[Account]bool)
+	accounts := make([]Account, 0)
+	for _, table := range tables {
+		for account := range table {
+			if !seen[account] {
+				seen[account] = true
+				accounts = append(accounts, account)
+			}
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i] < accounts[j] })
+	return accounts
+}
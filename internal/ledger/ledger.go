@@ -0,0 +1,190 @@
+// internal/ledger/ledger.go
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"monopoly/internal/storage"
+	"monopoly/pkg/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Account 账本中的账户标识
+type Account string
+
+// AccountExternal 系统外部账户，是充值和扣除的对方账户，余额的相反数即流入系统的金币总量
+const AccountExternal Account = "external"
+
+// UserAccountPrefix 用户钱包账户的标识前缀
+const UserAccountPrefix = "user:"
+
+// UserAccount 用户钱包账户
+func UserAccount(userID string) Account {
+	return Account(UserAccountPrefix + userID)
+}
+
+// EscrowAccount 游戏的托管账户，加入时托管的买入金额经此转入玩家的游戏内余额
+func EscrowAccount(gameID string) Account {
+	return Account("escrow:" + gameID)
+}
+
+// GameAccountPrefix 游戏内账户的标识前缀
+func GameAccountPrefix(gameID string) string {
+	return "game:" + gameID + ":"
+}
+
+// PlayerAccount 玩家在游戏内的金币余额
+func PlayerAccount(gameID, playerID string) Account {
+	return Account(GameAccountPrefix(gameID) + "player:" + playerID)
+}
+
+// PrizePoolAccount 游戏的奖池
+func PrizePoolAccount(gameID string) Account {
+	return Account(GameAccountPrefix(gameID) + "prizePool")
+}
+
+// BankAccount 玩家在游戏内的银行存款
+func BankAccount(gameID, playerID string) Account {
+	return Account(GameAccountPrefix(gameID) + "bank:" + playerID)
+}
+
+// Reason 分录的原因代码，游戏内的金币转移直接使用转移原因
+type Reason string
+
+const (
+	ReasonBuyIn           Reason = "buyIn"           // 托管的买入金额转为玩家的初始金币
+	ReasonCashOut         Reason = "cashOut"         // 离开或游戏结束时玩家的游戏内余额转回托管账户
+	ReasonEscrowCancelled Reason = "escrowCancelled" // 加入失败时撤销托管
+	ReasonAccountClosed   Reason = "accountClosed"   // 删除用户时余额转出系统
+)
+
+// Entry 一笔复式记账分录：Credit 账户转出 Amount，Debit 账户转入 Amount
+type Entry struct {
+	ID            int       `json:"id"`
+	Debit         Account   `json:"debit"`
+	Credit        Account   `json:"credit"`
+	Amount        int       `json:"amount"`
+	Reason        Reason    `json:"reason"`
+	GameID        string    `json:"gameId,omitempty"`        // 关联的游戏
+	EventIndex    int       `json:"eventIndex,omitempty"`    // 产生该分录的游戏事件序号
+	TransactionID string    `json:"transactionId,omitempty"` // 产生该分录的用户交易记录
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// Ledger 记录所有金币流动的复式账本，分录只追加不修改
+type Ledger struct {
+	entries   []Entry
+	balances  map[Account]int
+	byAccount map[Account][]int
+	store     storage.Store
+	mutex     sync.RWMutex
+}
+
+// NewLedger 创建空账本，每笔分录先写入存储再生效
+func NewLedger(store storage.Store) *Ledger {
+	return &Ledger{
+		entries:   make([]Entry, 0),
+		balances:  make(map[Account]int),
+		byAccount: make(map[Account][]int),
+		store:     store,
+	}
+}
+
+// Load 从存储恢复全部分录，需在记录新分录之前调用
+func (l *Ledger) Load() error {
+	records, err := l.store.List(storage.CollectionLedger)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]Entry, 0, len(records))
+	for _, raw := range records {
+		var entry Entry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, entry := range entries {
+		if entry.ID != len(l.entries)+1 {
+			return fmt.Errorf("ledger entry %d is missing", len(l.entries)+1)
+		}
+		l.apply(entry)
+	}
+	return nil
+}
+
+// Record 追加一笔分录，金额为0时不记录
+// 时间为空时使用当前时间，分录编号由账本分配
+func (l *Ledger) Record(entry Entry) error {
+	if entry.Amount == 0 {
+		return nil
+	}
+	if entry.Amount < 0 || entry.Debit == "" || entry.Credit == "" || entry.Debit == entry.Credit {
+		return utils.ErrInvalidInput
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	entry.ID = len(l.entries) + 1
+	if err := l.store.Put(storage.CollectionLedger, fmt.Sprintf("%012d", entry.ID), entry); err != nil {
+		return err
+	}
+	l.apply(entry)
+	return nil
+}
+
+// apply 追加分录并更新余额和账户索引，调用方需持有锁
+func (l *Ledger) apply(entry Entry) {
+	l.entries = append(l.entries, entry)
+	l.balances[entry.Credit] -= entry.Amount
+	l.balances[entry.Debit] += entry.Amount
+	l.byAccount[entry.Credit] = append(l.byAccount[entry.Credit], len(l.entries)-1)
+	l.byAccount[entry.Debit] = append(l.byAccount[entry.Debit], len(l.entries)-1)
+}
+
+// Balance 获取账户余额
+func (l *Ledger) Balance(account Account) int {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return l.balances[account]
+}
+
+// Entries 获取涉及指定账户的全部分录，按记录顺序排列
+func (l *Ledger) Entries(account Account) []Entry {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	entries := make([]Entry, 0, len(l.byAccount[account]))
+	for _, i := range l.byAccount[account] {
+		entries = append(entries, l.entries[i])
+	}
+	return entries
+}
+
+// Accounts 获取账本中出现过的、以 prefix 开头的账户
+func (l *Ledger) Accounts(prefix string) []Account {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	accounts := make([]Account, 0)
+	for account := range l.byAccount {
+		if strings.HasPrefix(string(account), prefix) {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts
+}
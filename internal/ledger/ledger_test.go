@@ -0,0 +1,116 @@
+// internal/ledger/ledger_test.go
+package ledger
+
+import (
+	"errors"
+	"monopoly/internal/storage"
+	"monopoly/pkg/utils"
+	"testing"
+)
+
+// newTestLedger 创建一个充值1000金币给用户 a 的账本
+func newTestLedger(t *testing.T) (*Ledger, storage.Store) {
+	t.Helper()
+
+	store := storage.NewMemoryStore()
+	l := NewLedger(store)
+	if err := l.Record(Entry{Debit: UserAccount("a"), Credit: AccountExternal, Amount: 1000, Reason: "add"}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	return l, store
+}
+
+func TestRecordRejectsInvalidEntries(t *testing.T) {
+	l, _ := newTestLedger(t)
+
+	tests := []struct {
+		name  string
+		entry Entry
+	}{
+		{"negative amount", Entry{Debit: UserAccount("a"), Credit: AccountExternal, Amount: -100}},
+		{"missing debit", Entry{Credit: AccountExternal, Amount: 100}},
+		{"missing credit", Entry{Debit: UserAccount("a"), Amount: 100}},
+		{"same account", Entry{Debit: UserAccount("a"), Credit: UserAccount("a"), Amount: 100}},
+	}
+	for _, tt := range tests {
+		if err := l.Record(tt.entry); !errors.Is(err, utils.ErrInvalidInput) {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, utils.ErrInvalidInput)
+		}
+	}
+
+	if report := l.Verify(); !report.Consistent() || report.Entries != 1 || report.Issued != 1000 {
+		t.Fatalf("got report %+v, want one consistent entry issuing 1000", report)
+	}
+}
+
+func TestVerifyDetectsInvalidStoredEntry(t *testing.T) {
+	_, store := newTestLedger(t)
+	if err := store.Put(storage.CollectionLedger, "000000000002", Entry{
+		ID: 2, Debit: UserAccount("a"), Credit: UserAccount("a"), Amount: 500,
+	}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	l := NewLedger(store)
+	if err := l.Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	report := l.Verify()
+	if report.Consistent() {
+		t.Fatalf("got consistent report %+v, want invalid entry reported", report)
+	}
+	if len(report.Discrepancies) != 1 || report.Discrepancies[0].Detail != "invalid entry" {
+		t.Fatalf("got discrepancies %+v, want one invalid entry", report.Discrepancies)
+	}
+}
+
+func TestVerifyDetectsCachedBalanceMismatch(t *testing.T) {
+	l, _ := newTestLedger(t)
+	l.balances[UserAccount("a")] += 50
+
+	report := l.Verify()
+	if len(report.Discrepancies) != 1 || report.Discrepancies[0].Account != UserAccount("a") {
+		t.Fatalf("got discrepancies %+v, want a mismatch on %s", report.Discrepancies, UserAccount("a"))
+	}
+}
+
+func TestLoadRejectsMissingEntry(t *testing.T) {
+	_, store := newTestLedger(t)
+	if err := store.Put(storage.CollectionLedger, "000000000003", Entry{
+		ID: 3, Debit: UserAccount("a"), Credit: AccountExternal, Amount: 100,
+	}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	if err := NewLedger(store).Load(); err == nil {
+		t.Fatal("loaded a ledger with a missing entry")
+	}
+}
+
+func TestReconcileDetectsMismatch(t *testing.T) {
+	l, _ := newTestLedger(t)
+	if err := l.Record(Entry{Debit: EscrowAccount("g1"), Credit: UserAccount("a"), Amount: 300, GameID: "g1"}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	actual := map[Account]int{UserAccount("a"): 700, EscrowAccount("g1"): 300}
+	if discrepancies := l.Reconcile(actual, nil); len(discrepancies) != 0 {
+		t.Fatalf("got discrepancies %+v, want none", discrepancies)
+	}
+
+	actual[UserAccount("a")] = 800
+	discrepancies := l.Reconcile(actual, nil)
+	if len(discrepancies) != 1 {
+		t.Fatalf("got discrepancies %+v, want one", discrepancies)
+	}
+	if d := discrepancies[0]; d.Account != UserAccount("a") || d.Ledger != 700 || d.Actual != 800 {
+		t.Fatalf("got discrepancy %+v, want ledger 700 and actual 800 for %s", d, UserAccount("a"))
+	}
+
+	// 只累加其他游戏的分录时托管账户余额为0
+	otherGames := func(entry Entry) bool { return entry.GameID != "g1" }
+	discrepancies = l.Reconcile(map[Account]int{EscrowAccount("g1"): 300}, otherGames)
+	if len(discrepancies) != 1 || discrepancies[0].Ledger != 0 {
+		t.Fatalf("got discrepancies %+v, want escrow mismatch with ledger 0", discrepancies)
+	}
+}
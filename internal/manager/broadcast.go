@@ -0,0 +1,261 @@
+// internal/manager/broadcast.go
+package manager
+
+import (
+	"encoding/json"
+	"log"
+	"monopoly/internal/game"
+	"sync"
+)
+
+// 推送消息类型
+const (
+	MessageSnapshot = "snapshot" // 完整的游戏状态
+	MessageEvent    = "event"    // 一次游戏状态变化
+	MessageAction   = "action"   // 一条新的游戏动作记录
+)
+
+// MaxPendingMessages 订阅者未读取的消息上限，超过后断开订阅，客户端需重连续传
+const MaxPendingMessages = 4096
+
+// Message 推送给订阅者的消息
+type Message struct {
+	Type        string          `json:"type"`
+	EventIndex  int             `json:"eventIndex"`  // 消息对应的事件序号，快照为当时最后一个事件的序号
+	ActionIndex int             `json:"actionIndex"` // 截至该消息的最后一个动作序号，尚无动作时为-1，重连时据此续传
+	Data        json.RawMessage `json:"data"`        // 快照为游戏，动作消息为动作，其余为事件
+}
+
+// Broadcaster 将游戏事件实时推送给订阅的玩家和观战者
+// 事件在持有游戏锁时加入各订阅者的队列，由订阅者各自的 goroutine 发送，慢速订阅者不会阻塞游戏
+type Broadcaster struct {
+	games       *GameManager
+	subscribers map[string]map[*Subscription]struct{}
+	closed      bool
+	mutex       sync.Mutex
+}
+
+// newBroadcaster 创建推送器，由游戏管理器注册为所有游戏的事件观察者
+func newBroadcaster(gm *GameManager) *Broadcaster {
+	return &Broadcaster{
+		games:       gm,
+		subscribers: make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe 订阅游戏的推送
+// lastAction 为客户端已收到的最后一个动作序号，小于0、超出范围或需补发的事件过多时先推送完整快照，
+// 否则补发该动作之后的全部事件，再继续推送新事件
+func (b *Broadcaster) Subscribe(gameID string, lastAction int) (*Subscription, error) {
+	g, err := b.games.GetGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := newSubscription(b, gameID)
+	g.View(func(g *game.Game) {
+		// 持有游戏锁时注册，保证补发的消息与之后推送的事件衔接
+		if after, ok := actionEventIndex(g, lastAction); ok && len(g.Events)-after <= MaxPendingMessages {
+			actionIndex := lastAction
+			for _, event := range g.Events[after+1:] {
+				if event.Type == game.EventActionRecorded {
+					actionIndex++
+				}
+				sub.push(eventMessage(event, actionIndex))
+			}
+		} else {
+			sub.push(snapshotMessage(g))
+		}
+
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+
+		if b.closed {
+			sub.close()
+			return
+		}
+		if b.subscribers[gameID] == nil {
+			b.subscribers[gameID] = make(map[*Subscription]struct{})
+		}
+		b.subscribers[gameID][sub] = struct{}{}
+	})
+	return sub, nil
+}
+
+// Close 断开所有订阅并拒绝新的订阅，停机开始时调用，使推送连接不阻塞停机
+func (b *Broadcaster) Close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	for gameID, subs := range b.subscribers {
+		for sub := range subs {
+			sub.close()
+		}
+		delete(b.subscribers, gameID)
+	}
+}
+
+// handleEvent 将事件推送给游戏的订阅者，调用时持有游戏锁
+func (b *Broadcaster) handleEvent(g *game.Game, e *game.Event) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	subs := b.subscribers[g.ID]
+	if len(subs) == 0 {
+		return
+	}
+
+	message := eventMessage(e, len(g.Actions)-1)
+	for sub := range subs {
+		if !sub.push(message) {
+			log.Printf("broadcast: drop slow subscriber of game %s", g.ID)
+			delete(subs, sub)
+		}
+	}
+}
+
+// unsubscribe 移除订阅
+func (b *Broadcaster) unsubscribe(sub *Subscription) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if subs := b.subscribers[sub.GameID]; subs != nil {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(b.subscribers, sub.GameID)
+		}
+	}
+}
+
+// actionEventIndex 查找记录第 actionIndex 个动作的事件序号
+func actionEventIndex(g *game.Game, actionIndex int) (int, bool) {
+	if actionIndex < 0 || actionIndex >= len(g.Actions) {
+		return 0, false
+	}
+	recorded := 0
+	for _, event := range g.Events {
+		if event.Type != game.EventActionRecorded {
+			continue
+		}
+		if recorded == actionIndex {
+			return event.Index, true
+		}
+		recorded++
+	}
+	return 0, false
+}
+
+// snapshotMessage 生成完整快照消息，调用方需持有游戏锁
+func snapshotMessage(g *game.Game) Message {
+	return Message{
+		Type:        MessageSnapshot,
+		EventIndex:  len(g.Events) - 1,
+		ActionIndex: len(g.Actions) - 1,
+		Data:        marshalMessageData(g),
+	}
+}
+
+// eventMessage 生成事件消息，动作记录事件推送动作本身，调用方需持有游戏锁
+// actionIndex 为截至该事件的最后一个动作序号
+func eventMessage(e *game.Event, actionIndex int) Message {
+	if recorded, ok := e.Payload.(*game.ActionRecorded); ok {
+		return Message{
+			Type:        MessageAction,
+			EventIndex:  e.Index,
+			ActionIndex: actionIndex,
+			Data:        marshalMessageData(recorded.Action),
+		}
+	}
+	return Message{
+		Type:        MessageEvent,
+		EventIndex:  e.Index,
+		ActionIndex: actionIndex,
+		Data:        marshalMessageData(e),
+	}
+}
+
+// marshalMessageData 在持有游戏锁时序列化消息数据，避免发送时读取到之后的状态
+func marshalMessageData(v interface{}) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("broadcast: marshal message: %v", err)
+		return json.RawMessage("null")
+	}
+	return data
+}
+
+// Subscription 一个订阅者的消息队列
+type Subscription struct {
+	GameID      string
+	broadcaster *Broadcaster
+	queue       []Message
+	ready       chan struct{}
+	closed      bool
+	mutex       sync.Mutex
+}
+
+// newSubscription 创建订阅
+func newSubscription(b *Broadcaster, gameID string) *Subscription {
+	return &Subscription{
+		GameID:      gameID,
+		broadcaster: b,
+		ready:       make(chan struct{}, 1),
+	}
+}
+
+// Ready 有新消息或订阅被关闭时收到通知
+func (s *Subscription) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Next 取出所有待发送的消息，订阅已关闭且消息取完时返回 false
+func (s *Subscription) Next() ([]Message, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	messages := s.queue
+	s.queue = nil
+	return messages, len(messages) > 0 || !s.closed
+}
+
+// Close 取消订阅
+func (s *Subscription) Close() {
+	s.broadcaster.unsubscribe(s)
+	s.close()
+}
+
+// push 加入待发送的消息，积压超过上限时关闭订阅并返回 false
+func (s *Subscription) push(message Message) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return false
+	}
+	if len(s.queue) >= MaxPendingMessages {
+		s.closed = true
+		s.notify()
+		return false
+	}
+	s.queue = append(s.queue, message)
+	s.notify()
+	return true
+}
+
+// close 关闭订阅，已排队的消息仍可取出
+func (s *Subscription) close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.closed = true
+	s.notify()
+}
+
+// notify 通知订阅者，调用方需持有订阅锁
+func (s *Subscription) notify() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,138 @@
+// internal/manager/ledger.go
+package manager
+
+import (
+	"log"
+	"monopoly/internal/game"
+	"monopoly/internal/ledger"
+	"monopoly/internal/user"
+)
+
+// LedgerRecorder 将游戏事件中的金币流动记入账本
+// 买入金额从托管账户转入玩家账户，游戏内转移在玩家、奖池和存款账户之间记账，
+// 玩家离开、游戏结束或被放弃时玩家的游戏内余额转回托管账户，再由结算退还用户
+type LedgerRecorder struct {
+	ledger *ledger.Ledger
+}
+
+// NewLedgerRecorder 创建账本记录器并订阅所有游戏的事件
+// 需要在 NewSettlement 之前创建，使结算前玩家余额已转回托管账户
+func NewLedgerRecorder(gm *GameManager, l *ledger.Ledger) *LedgerRecorder {
+	r := &LedgerRecorder{ledger: l}
+	gm.Observe(r.handleEvent)
+	return r
+}
+
+// handleEvent 记录事件产生的分录，调用时持有游戏锁
+func (r *LedgerRecorder) handleEvent(g *game.Game, e *game.Event) {
+	switch payload := e.Payload.(type) {
+	case *game.PlayerJoined:
+		r.record(g, e, ledger.EscrowAccount(g.ID), ledger.PlayerAccount(g.ID, payload.PlayerID), payload.Coins, ledger.ReasonBuyIn)
+	case *game.CoinsTransferred:
+		r.record(g, e, gameAccount(g.ID, payload.From), gameAccount(g.ID, payload.To), payload.Amount, ledger.Reason(payload.Reason))
+	case *game.PlayerLeft:
+		r.cashOut(g, e, payload.PlayerID)
+	case *game.GameEnded, *game.GameAbandoned:
+		for _, id := range g.TurnOrder {
+			r.cashOut(g, e, id)
+		}
+	}
+}
+
+// cashOut 将玩家的游戏内余额和存款转回托管账户
+func (r *LedgerRecorder) cashOut(g *game.Game, e *game.Event, playerID string) {
+	for _, account := range []ledger.Account{
+		ledger.BankAccount(g.ID, playerID),
+		ledger.PlayerAccount(g.ID, playerID),
+	} {
+		r.record(g, e, account, ledger.EscrowAccount(g.ID), r.ledger.Balance(account), ledger.ReasonCashOut)
+	}
+}
+
+// record 记入一笔引用游戏事件的分录
+func (r *LedgerRecorder) record(g *game.Game, e *game.Event, from, to ledger.Account, amount int, reason ledger.Reason) {
+	err := r.ledger.Record(ledger.Entry{
+		Debit:      to,
+		Credit:     from,
+		Amount:     amount,
+		Reason:     reason,
+		GameID:     g.ID,
+		EventIndex: e.Index,
+		Timestamp:  e.Timestamp,
+	})
+	if err != nil {
+		log.Printf("ledger: game %s event %d: %v", g.ID, e.Index, err)
+	}
+}
+
+// gameAccount 将游戏内的资金账户映射为账本账户
+func gameAccount(gameID, account string) ledger.Account {
+	if account == game.AccountPrizePool {
+		return ledger.PrizePoolAccount(gameID)
+	}
+	if playerID, ok := game.DepositOwner(account); ok {
+		return ledger.BankAccount(gameID, playerID)
+	}
+	return ledger.PlayerAccount(gameID, account)
+}
+
+// CheckLedger 检查账本一致性：分录借贷平衡且总和为0，用户钱包余额与账本一致，没有未完成的结算，
+// 进行中的游戏内余额与重放事件得到的状态一致，已结束或被放弃的游戏所有游戏内账户和托管账户均已清零
+func CheckLedger(gm *GameManager, um *user.Manager, l *ledger.Ledger) (*ledger.Report, error) {
+	report := l.Verify()
+	report.Discrepancies = append(report.Discrepancies, um.ReconcileLedger()...)
+
+	// 未完成的结算仍有金币留在托管账户中
+	for _, pending := range um.PendingSettlements() {
+		escrow := ledger.EscrowAccount(pending.GameID)
+		report.Discrepancies = append(report.Discrepancies, ledger.Discrepancy{
+			Account: escrow,
+			Ledger:  l.Balance(escrow),
+			Detail:  "settlement pending",
+		})
+	}
+
+	// 被跳过的游戏无法结算，托管的金币留在托管账户中
+	for _, gameID := range gm.SkippedGames() {
+		escrow := ledger.EscrowAccount(gameID)
+		report.Discrepancies = append(report.Discrepancies, ledger.Discrepancy{
+			Account: escrow,
+			Ledger:  l.Balance(escrow),
+			Detail:  "game log not restored",
+		})
+	}
+
+	for _, g := range gm.ListGames() {
+		// 基于事件日志快照重建游戏状态，只比对快照范围内的事件产生的分录
+		events := g.GetEvents()
+		state, err := game.Replay(events, len(events)-1)
+		if err != nil {
+			return nil, err
+		}
+		last := events[len(events)-1].Index
+
+		actual := make(map[ledger.Account]int)
+		for _, account := range l.Accounts(ledger.GameAccountPrefix(state.ID)) {
+			actual[account] = 0
+		}
+
+		switch state.Status {
+		case game.StatusFinished, game.StatusAbandoned:
+			actual[ledger.EscrowAccount(state.ID)] = 0
+		default:
+			for _, id := range state.TurnOrder {
+				player := state.Players[id]
+				actual[ledger.PlayerAccount(state.ID, id)] = player.Coins
+				actual[ledger.BankAccount(state.ID, id)] = player.Deposit
+			}
+			actual[ledger.PrizePoolAccount(state.ID)] = state.PrizePool
+		}
+
+		gameID := state.ID
+		include := func(entry ledger.Entry) bool {
+			return entry.GameID == gameID && entry.EventIndex <= last
+		}
+		report.Discrepancies = append(report.Discrepancies, l.Reconcile(actual, include)...)
+	}
+	return report, nil
+}
@@ -2,29 +2,75 @@
 package manager
 
 import (
+	"errors"
+	"log"
 	"monopoly/internal/game"
+	"monopoly/internal/storage"
 	"monopoly/pkg/utils"
+	"sort"
 	"sync"
 )
 
 type GameManager struct {
-	games map[string]*game.Game
-	mutex sync.RWMutex
+	games       map[string]*game.Game
+	skipped     map[string]bool // 事件日志无法恢复的游戏，其ID不能再用于创建游戏
+	maps        *game.MapRegistry
+	store       storage.Store
+	clock       game.Clock
+	scheduler   *Scheduler
+	broadcaster *Broadcaster
+	observers   []game.Observer
+	mutex       sync.RWMutex
 }
 
-func NewGameManager() *GameManager {
-	return &GameManager{
-		games: make(map[string]*game.Game),
+// NewGameManager 创建游戏管理器，所有游戏的事件日志写入存储
+func NewGameManager(maps *game.MapRegistry, store storage.Store) *GameManager {
+	return NewGameManagerWithClock(maps, store, game.SystemClock)
+}
+
+// NewGameManagerWithClock 使用指定时钟创建游戏管理器，便于测试超时逻辑
+func NewGameManagerWithClock(maps *game.MapRegistry, store storage.Store, clock game.Clock) *GameManager {
+	gm := &GameManager{
+		games:   make(map[string]*game.Game),
+		skipped: make(map[string]bool),
+		maps:    maps,
+		store:   store,
+		clock:   clock,
 	}
+	gm.broadcaster = newBroadcaster(gm)
+	gm.observers = []game.Observer{gm.saveEvent, gm.broadcaster.handleEvent}
+	gm.scheduler = NewScheduler(gm, DefaultSchedulerInterval)
+	return gm
 }
 
-func (gm *GameManager) CreateGame(id string) *game.Game {
+// CreateGame 使用指定地图创建游戏，地图名称为空时使用默认地图，hostID 为创建游戏的用户
+// 游戏ID不能为空，也不能与已有的游戏重复，否则两局游戏的事件日志会混在一起
+func (gm *GameManager) CreateGame(id, hostID string, seed int64, settings game.GameSettings, mapName string) (*game.Game, error) {
+	if id == "" {
+		return nil, utils.ErrInvalidInput
+	}
+
+	gameMap, err := gm.maps.Get(mapName)
+	if err != nil {
+		return nil, err
+	}
+
 	gm.mutex.Lock()
 	defer gm.mutex.Unlock()
 
-	newGame := game.NewGame(id)
+	if _, exists := gm.games[id]; exists || gm.skipped[id] {
+		return nil, utils.ErrGameExists
+	}
+
+	newGame := game.NewGame(id, hostID, seed, settings, gameMap, gm.clock)
+	for _, event := range newGame.GetEvents() {
+		gm.saveEvent(newGame, event)
+	}
+	for _, observer := range gm.observers {
+		newGame.Observe(observer)
+	}
 	gm.games[id] = newGame
-	return newGame
+	return newGame, nil
 }
 
 func (gm *GameManager) GetGame(id string) (*game.Game, error) {
@@ -38,3 +84,74 @@ func (gm *GameManager) GetGame(id string) (*game.Game, error) {
 
 	return game, nil
 }
+
+// ListGames 获取所有游戏
+func (gm *GameManager) ListGames() []*game.Game {
+	gm.mutex.RLock()
+	defer gm.mutex.RUnlock()
+
+	games := make([]*game.Game, 0, len(gm.games))
+	for _, g := range gm.games {
+		games = append(games, g)
+	}
+	return games
+}
+
+// SkippedGames 获取启动时因事件日志无法恢复而被跳过的游戏ID，按ID排序
+func (gm *GameManager) SkippedGames() []string {
+	gm.mutex.RLock()
+	defer gm.mutex.RUnlock()
+
+	ids := make([]string, 0, len(gm.skipped))
+	for id := range gm.skipped {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Observe 注册事件观察者，接收所有已有和之后创建的游戏产生的事件
+func (gm *GameManager) Observe(observer game.Observer) {
+	gm.mutex.Lock()
+	defer gm.mutex.Unlock()
+
+	gm.observers = append(gm.observers, observer)
+	for _, g := range gm.games {
+		g.Observe(observer)
+	}
+}
+
+// Scheduler 获取游戏管理器的超时调度器
+func (gm *GameManager) Scheduler() *Scheduler {
+	return gm.scheduler
+}
+
+// Broadcaster 获取游戏管理器的实时推送器
+func (gm *GameManager) Broadcaster() *Broadcaster {
+	return gm.broadcaster
+}
+
+// Shutdown 停止超时调度器并以停机原因暂停所有进行中的游戏，停机前调用，使计时在停机期间不再流逝
+func (gm *GameManager) Shutdown() {
+	gm.scheduler.Stop()
+
+	for _, g := range gm.ListGames() {
+		if err := g.Pause(game.PauseShutdown); err != nil && !errors.Is(err, utils.ErrInvalidGameState) {
+			log.Printf("shutdown: pause game %s: %v", g.ID, err)
+		}
+	}
+}
+
+// ResumePaused 恢复停机时暂停的游戏，重启并从存储恢复游戏后调用，返回恢复的游戏数量
+// 主持人暂停的游戏保持暂停
+func (gm *GameManager) ResumePaused() int {
+	resumed := 0
+	for _, g := range gm.ListGames() {
+		if err := g.Resume(game.PauseShutdown); err == nil {
+			resumed++
+		} else if !errors.Is(err, utils.ErrInvalidGameState) {
+			log.Printf("resume game %s: %v", g.ID, err)
+		}
+	}
+	return resumed
+}
@@ -0,0 +1,139 @@
+// internal/manager/manager_test.go
+package manager
+
+import (
+	"errors"
+	"monopoly/internal/game"
+	"monopoly/internal/storage"
+	"monopoly/pkg/utils"
+	"testing"
+	"time"
+)
+
+func TestCreateGameRejectsEmptyAndDuplicateIDs(t *testing.T) {
+	gm := newTestManager(t, &testClock{now: time.Unix(1000, 0)})
+
+	if _, err := gm.CreateGame("", "a", 1, game.DefaultSettings(), "small"); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("got error %v, want %v", err, utils.ErrInvalidInput)
+	}
+
+	original := startTestGame(t, gm)
+	if _, err := gm.CreateGame(original.ID, "mallory", 2, game.DefaultSettings(), "small"); !errors.Is(err, utils.ErrGameExists) {
+		t.Fatalf("got error %v, want %v", err, utils.ErrGameExists)
+	}
+
+	g, err := gm.GetGame(original.ID)
+	if err != nil {
+		t.Fatalf("get game: %v", err)
+	}
+	if g != original || g.HostID != "a" || g.Status != game.StatusPlaying {
+		t.Fatalf("game %s was replaced: host %s, status %s", g.ID, g.HostID, g.Status)
+	}
+}
+
+func TestLoadSkipsGameWithMissingEvent(t *testing.T) {
+	store := &failingStore{Store: storage.NewMemoryStore()}
+	clock := &testClock{now: time.Unix(1000, 0)}
+	gm := newTestManagerWithStore(t, store, clock)
+
+	healthy, err := gm.CreateGame("healthy", "a", 1, game.DefaultSettings(), "small")
+	if err != nil {
+		t.Fatalf("create game: %v", err)
+	}
+
+	// 游戏 g1 的第二条事件写入失败，之后的事件正常写入
+	store.failOn(storage.CollectionEvents, 2)
+	broken := startTestGame(t, gm)
+	if err := healthy.AddPlayer(game.NewPlayer("c", "c", 5000)); err != nil {
+		t.Fatalf("add player: %v", err)
+	}
+
+	restarted := newTestManagerWithStore(t, store.Store, clock)
+	if err := restarted.Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if _, err := restarted.GetGame(broken.ID); err == nil {
+		t.Fatalf("game %s with a missing event was restored", broken.ID)
+	}
+	if skipped := restarted.SkippedGames(); len(skipped) != 1 || skipped[0] != broken.ID {
+		t.Fatalf("got skipped games %v, want [%s]", skipped, broken.ID)
+	}
+	if g, err := restarted.GetGame(healthy.ID); err != nil || len(g.Players) != 1 {
+		t.Fatalf("healthy game not restored: %v", err)
+	}
+
+	// 跳过的游戏ID不能再用于创建游戏，避免与残留的事件日志混在一起
+	if _, err := restarted.CreateGame(broken.ID, "a", 1, game.DefaultSettings(), "small"); !errors.Is(err, utils.ErrGameExists) {
+		t.Fatalf("got error %v, want %v", err, utils.ErrGameExists)
+	}
+}
+
+func TestRestartResumesOnlyShutdownPauses(t *testing.T) {
+	clock := &testClock{now: time.Unix(1000, 0)}
+	gm := newTestManager(t, clock)
+	paused := startTestGame(t, gm)
+	if err := paused.Pause(game.PauseHost); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	running, err := gm.CreateGame("g2", "a", 2, game.DefaultSettings(), "small")
+	if err != nil {
+		t.Fatalf("create game: %v", err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if err := running.AddPlayer(game.NewPlayer(id, id, 5000)); err != nil {
+			t.Fatalf("add player %s: %v", id, err)
+		}
+	}
+	if err := running.StartGame(); err != nil {
+		t.Fatalf("start game: %v", err)
+	}
+
+	gm.Shutdown()
+	if running.Status != game.StatusPaused || running.PauseReason != game.PauseShutdown {
+		t.Fatalf("running game: got status %s paused by %q, want paused by shutdown", running.Status, running.PauseReason)
+	}
+	if paused.PauseReason != game.PauseHost {
+		t.Fatalf("host pause replaced by %q", paused.PauseReason)
+	}
+
+	if resumed := gm.ResumePaused(); resumed != 1 {
+		t.Fatalf("resumed %d games, want 1", resumed)
+	}
+	if running.Status != game.StatusPlaying {
+		t.Fatalf("running game: got status %s, want %s", running.Status, game.StatusPlaying)
+	}
+	if paused.Status != game.StatusPaused || paused.PauseReason != game.PauseHost {
+		t.Fatalf("host-paused game: got status %s paused by %q, want it still paused by the host", paused.Status, paused.PauseReason)
+	}
+}
+
+func TestRestartKeepsRemainingTurnTime(t *testing.T) {
+	store := storage.NewMemoryStore()
+	clock := &testClock{now: time.Unix(1000, 0)}
+	gm := newTestManagerWithStore(t, store, clock)
+	g := startTestGame(t, gm)
+	current := g.CurrentPlayerID
+	clock.Advance(10 * time.Second)
+	turnLeft := g.GetTurnTimeLeft()
+
+	gm.Shutdown()
+	clock.Advance(time.Hour)
+
+	restarted := newTestManagerWithStore(t, store, clock)
+	if err := restarted.Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	restored, err := restarted.GetGame(g.ID)
+	if err != nil {
+		t.Fatalf("get game: %v", err)
+	}
+	if restored.Status != game.StatusPaused || restored.PauseReason != game.PauseShutdown {
+		t.Fatalf("got status %s paused by %q, want paused by shutdown", restored.Status, restored.PauseReason)
+	}
+	if resumed := restarted.ResumePaused(); resumed != 1 {
+		t.Fatalf("resumed %d games, want 1", resumed)
+	}
+	if restored.CurrentPlayerID != current || restored.GetTurnTimeLeft() != turnLeft {
+		t.Fatalf("got player %s with %ds left, want %s with %ds", restored.CurrentPlayerID, restored.GetTurnTimeLeft(), current, turnLeft)
+	}
+}
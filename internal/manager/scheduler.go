@@ -0,0 +1,84 @@
+// internal/manager/scheduler.go
+package manager
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultSchedulerInterval 默认的超时检查间隔
+const DefaultSchedulerInterval = time.Second
+
+// Scheduler 后台超时调度器
+// 定期检查所有进行中的游戏，回合超时自动进入下一回合，游戏超时自动结束游戏
+type Scheduler struct {
+	manager  *GameManager
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+	mutex    sync.Mutex
+}
+
+// NewScheduler 创建超时调度器
+func NewScheduler(gm *GameManager, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		manager:  gm,
+		interval: interval,
+	}
+}
+
+// Start 启动后台检查，重复调用无效
+func (s *Scheduler) Start() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.done)
+}
+
+// Stop 停止后台检查并等待其退出
+func (s *Scheduler) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stop == nil {
+		return
+	}
+
+	close(s.stop)
+	<-s.done
+	s.stop = nil
+	s.done = nil
+}
+
+// Tick 对所有游戏执行一次超时检查
+// 超时判断使用游戏管理器的时钟，测试中可注入时钟后直接调用
+func (s *Scheduler) Tick() {
+	for _, g := range s.manager.ListGames() {
+		if err := g.CheckTimeouts(); err != nil {
+			log.Printf("scheduler: game %s: %v", g.ID, err)
+		}
+	}
+}
+
+func (s *Scheduler) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.Tick()
+		case <-stop:
+			return
+		}
+	}
+}
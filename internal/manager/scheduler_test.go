@@ -0,0 +1,198 @@
+// internal/manager/scheduler_test.go
+package manager
+
+import (
+	"monopoly/internal/game"
+	"monopoly/internal/storage"
+	"testing"
+	"time"
+)
+
+// testClock 可手动推进的时钟
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// newTestManager 使用指定时钟和内存存储创建游戏管理器
+func newTestManager(t *testing.T, clock game.Clock) *GameManager {
+	t.Helper()
+
+	return newTestManagerWithStore(t, storage.NewMemoryStore(), clock)
+}
+
+// newTestManagerWithStore 使用指定存储和时钟创建游戏管理器
+func newTestManagerWithStore(t *testing.T, store storage.Store, clock game.Clock) *GameManager {
+	t.Helper()
+
+	maps, err := game.LoadMaps("../../maps")
+	if err != nil {
+		t.Fatalf("load maps: %v", err)
+	}
+	return NewGameManagerWithClock(maps, store, clock)
+}
+
+// startTestGame 在小地图上创建并开始一局两人游戏
+func startTestGame(t *testing.T, gm *GameManager) *game.Game {
+	t.Helper()
+
+	g, err := gm.CreateGame("g1", "a", 1, game.DefaultSettings(), "small")
+	if err != nil {
+		t.Fatalf("create game: %v", err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if err := g.AddPlayer(game.NewPlayer(id, id, 5000)); err != nil {
+			t.Fatalf("add player %s: %v", id, err)
+		}
+	}
+	if err := g.StartGame(); err != nil {
+		t.Fatalf("start game: %v", err)
+	}
+	return g
+}
+
+// lastAction 获取最后一条动作记录
+func lastAction(t *testing.T, g *game.Game) *game.GameAction {
+	t.Helper()
+
+	if len(g.Actions) == 0 {
+		t.Fatal("no actions recorded")
+	}
+	return g.Actions[len(g.Actions)-1]
+}
+
+func TestSchedulerTurnTimeout(t *testing.T) {
+	clock := &testClock{now: time.Unix(1000, 0)}
+	gm := newTestManager(t, clock)
+	g := startTestGame(t, gm)
+	first := g.CurrentPlayerID
+	turnTimeout := time.Duration(g.Settings.TurnTimeout)
+
+	clock.Advance(turnTimeout - time.Second)
+	gm.Scheduler().Tick()
+	if g.CurrentPlayerID != first {
+		t.Fatalf("turn advanced before timeout: current player %s", g.CurrentPlayerID)
+	}
+	if len(g.Actions) != 0 {
+		t.Fatalf("got %d actions before timeout, want 0", len(g.Actions))
+	}
+
+	clock.Advance(time.Second)
+	gm.Scheduler().Tick()
+	if g.CurrentPlayerID == first {
+		t.Fatal("turn did not advance after timeout")
+	}
+	if !g.CurrentTurnStarted.Equal(clock.Now()) {
+		t.Fatalf("turn started at %v, want %v", g.CurrentTurnStarted, clock.Now())
+	}
+
+	action := lastAction(t, g)
+	if action.Type != game.ActionTurnTimeout || action.PlayerID != first {
+		t.Fatalf("got action %s by %s, want %s by %s", action.Type, action.PlayerID, game.ActionTurnTimeout, first)
+	}
+}
+
+func TestSchedulerGameTimeout(t *testing.T) {
+	clock := &testClock{now: time.Unix(1000, 0)}
+	gm := newTestManager(t, clock)
+	g := startTestGame(t, gm)
+	current := g.CurrentPlayerID
+
+	clock.Advance(time.Duration(g.Settings.GameTimeout))
+	gm.Scheduler().Tick()
+	if g.Status != game.StatusFinished {
+		t.Fatalf("got status %s, want %s", g.Status, game.StatusFinished)
+	}
+	if len(g.Ranking) != 2 {
+		t.Fatalf("got ranking %v, want both players", g.Ranking)
+	}
+	if g.PrizePool != 0 {
+		t.Fatalf("prize pool %d not paid out", g.PrizePool)
+	}
+
+	var timeout *game.GameAction
+	for _, action := range g.Actions {
+		if action.Type == game.ActionGameTimeout {
+			timeout = action
+		}
+	}
+	if timeout == nil || timeout.PlayerID != current {
+		t.Fatalf("game timeout not recorded for current player %s: %+v", current, timeout)
+	}
+	if action := lastAction(t, g); action.Type != "gameEnd" {
+		t.Fatalf("got last action %s, want gameEnd", action.Type)
+	}
+
+	// 已结束的游戏不再产生动作
+	actions := len(g.Actions)
+	clock.Advance(time.Hour)
+	gm.Scheduler().Tick()
+	if len(g.Actions) != actions {
+		t.Fatal("finished game recorded new actions")
+	}
+}
+
+func TestSchedulerLobbyTimeout(t *testing.T) {
+	clock := &testClock{now: time.Unix(1000, 0)}
+	gm := newTestManager(t, clock)
+	g, err := gm.CreateGame("g1", "a", 1, game.DefaultSettings(), "small")
+	if err != nil {
+		t.Fatalf("create game: %v", err)
+	}
+
+	clock.Advance(time.Duration(g.Settings.LobbyTimeout) - time.Second)
+	gm.Scheduler().Tick()
+	if g.Status != game.StatusWaiting {
+		t.Fatalf("got status %s before lobby timeout, want %s", g.Status, game.StatusWaiting)
+	}
+
+	clock.Advance(time.Second)
+	gm.Scheduler().Tick()
+	if g.Status != game.StatusAbandoned {
+		t.Fatalf("got status %s, want %s", g.Status, game.StatusAbandoned)
+	}
+}
+
+func TestSchedulerStartStop(t *testing.T) {
+	gm := newTestManager(t, game.SystemClock)
+	scheduler := NewScheduler(gm, time.Millisecond)
+
+	scheduler.Start()
+	scheduler.Start()
+	scheduler.Stop()
+	scheduler.Stop()
+}
+
+func TestSchedulerHostPauseTimeout(t *testing.T) {
+	clock := &testClock{now: time.Unix(1000, 0)}
+	gm := newTestManager(t, clock)
+	g := startTestGame(t, gm)
+	if err := g.Pause(game.PauseHost); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+
+	clock.Advance(time.Duration(g.Settings.PauseTimeout) - time.Second)
+	gm.Scheduler().Tick()
+	if g.Status != game.StatusPaused {
+		t.Fatalf("got status %s before pause timeout, want %s", g.Status, game.StatusPaused)
+	}
+
+	clock.Advance(time.Second)
+	gm.Scheduler().Tick()
+	if g.Status != game.StatusPlaying {
+		t.Fatalf("got status %s after pause timeout, want %s", g.Status, game.StatusPlaying)
+	}
+	if action := lastAction(t, g); action.Type != game.ActionPauseTimeout {
+		t.Fatalf("got action %s, want %s", action.Type, game.ActionPauseTimeout)
+	}
+	if left := g.GetTurnTimeLeft(); left != int(time.Duration(g.Settings.TurnTimeout).Seconds()) {
+		t.Fatalf("got %ds left in the turn, want the full turn kept", left)
+	}
+}
@@ -0,0 +1,96 @@
+// internal/manager/settlement.go
+package manager
+
+import (
+	"log"
+	"monopoly/internal/game"
+	"monopoly/internal/user"
+	"sync"
+)
+
+// Settlement 在用户钱包与游戏之间结算金币
+// 加入游戏时托管买入金额，玩家离开或游戏被放弃时退还，游戏结束时返还最终金币并记录名次
+type Settlement struct {
+	games *GameManager
+	users *user.Manager
+	mutex sync.Mutex
+}
+
+// NewSettlement 创建结算器并订阅所有游戏的事件
+func NewSettlement(gm *GameManager, um *user.Manager) *Settlement {
+	s := &Settlement{
+		games: gm,
+		users: um,
+	}
+	gm.Observe(s.handleEvent)
+	return s
+}
+
+// Join 托管用户的买入金额并以此作为初始金币加入游戏，加入失败时撤销托管
+func (s *Settlement) Join(gameID, userID string) (*game.Game, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	u, err := s.users.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := s.games.GetGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	buyIn := g.GetSettings().BuyIn
+	if err := s.users.EscrowBuyIn(u.ID, gameID, buyIn); err != nil {
+		return nil, err
+	}
+
+	if err := g.AddPlayer(game.NewPlayer(u.ID, u.Name, buyIn)); err != nil {
+		if cancelErr := s.users.CancelEscrow(u.ID, gameID); cancelErr != nil {
+			log.Printf("settlement: cancel escrow of %s in game %s: %v", u.ID, gameID, cancelErr)
+		}
+		return nil, err
+	}
+	return g, nil
+}
+
+// UpdateSettings 修改等待开始的游戏的规则设置
+// 与加入游戏互斥，避免玩家按修改前的买入金额托管后才加入游戏
+func (s *Settlement) UpdateSettings(gameID string, settings game.GameSettings) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	g, err := s.games.GetGame(gameID)
+	if err != nil {
+		return err
+	}
+	return g.UpdateSettings(settings)
+}
+
+// handleEvent 根据游戏事件结算，调用时持有游戏锁
+// 批量结算中途失败时保留未完成的结算记录，启动时重试
+func (s *Settlement) handleEvent(g *game.Game, e *game.Event) {
+	switch payload := e.Payload.(type) {
+	case *game.PlayerLeft:
+		if err := s.users.RefundBuyIn(payload.PlayerID, g.ID); err != nil {
+			log.Printf("settlement: refund %s in game %s: %v", payload.PlayerID, g.ID, err)
+		}
+	case *game.GameAbandoned:
+		if err := s.users.RefundGame(g.ID); err != nil {
+			log.Printf("settlement: refund game %s: %v", g.ID, err)
+		}
+	case *game.GameEnded:
+		results := make([]user.GameResult, 0, len(payload.Ranking))
+		for i, id := range payload.Ranking {
+			results = append(results, user.GameResult{
+				UserID: id,
+				Coins:  g.Players[id].Coins,
+				Rank:   i + 1,
+			})
+		}
+		if err := s.users.SettleGame(g.ID, results); err != nil {
+			log.Printf("settlement: settle game %s: %v", g.ID, err)
+		}
+	}
+}
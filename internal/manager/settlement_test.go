@@ -0,0 +1,322 @@
+// internal/manager/settlement_test.go
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"monopoly/internal/game"
+	"monopoly/internal/ledger"
+	"monopoly/internal/storage"
+	"monopoly/internal/user"
+	"monopoly/pkg/utils"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errStoreUnavailable = errors.New("store unavailable")
+
+// failingStore 在指定集合的第 failAt 次写入时失败一次
+type failingStore struct {
+	storage.Store
+	collection string
+	failAt     int
+	puts       int
+}
+
+func (s *failingStore) Put(collection, key string, value interface{}) error {
+	if collection == s.collection {
+		s.puts++
+		if s.puts == s.failAt {
+			return errStoreUnavailable
+		}
+	}
+	return s.Store.Put(collection, key, value)
+}
+
+// failOn 使集合的第 n 次写入失败
+func (s *failingStore) failOn(collection string, n int) {
+	s.collection = collection
+	s.failAt = n
+	s.puts = 0
+}
+
+// settlementFixture 共享同一存储的账本、用户管理器和游戏管理器
+type settlementFixture struct {
+	store      *failingStore
+	ledger     *ledger.Ledger
+	users      *user.Manager
+	games      *GameManager
+	settlement *Settlement
+}
+
+// newSettlementFixture 创建结算测试环境，用户 a、b、c 各有10000金币并加入游戏 g1
+func newSettlementFixture(t *testing.T) (*settlementFixture, *game.Game) {
+	t.Helper()
+
+	store := &failingStore{Store: storage.NewMemoryStore()}
+	l := ledger.NewLedger(store)
+	um := user.NewManager(l, store)
+	gm := newTestManagerWithStore(t, store, &testClock{now: time.Unix(1000, 0)})
+	NewLedgerRecorder(gm, l)
+	settlement := NewSettlement(gm, um)
+
+	g, err := gm.CreateGame("g1", "a", 1, game.DefaultSettings(), "small")
+	if err != nil {
+		t.Fatalf("create game: %v", err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if err := um.CreateUser(&user.User{ID: id, Name: id, Coins: 10000}); err != nil {
+			t.Fatalf("create user %s: %v", id, err)
+		}
+		if _, err := settlement.Join(g.ID, id); err != nil {
+			t.Fatalf("join %s: %v", id, err)
+		}
+	}
+	return &settlementFixture{store: store, ledger: l, users: um, games: gm, settlement: settlement}, g
+}
+
+// restart 从存储重新加载账本和用户，模拟服务重启
+func (f *settlementFixture) restart(t *testing.T) {
+	t.Helper()
+
+	f.ledger = ledger.NewLedger(f.store.Store)
+	if err := f.ledger.Load(); err != nil {
+		t.Fatalf("load ledger: %v", err)
+	}
+	f.users = user.NewManager(f.ledger, f.store.Store)
+	if err := f.users.Load(); err != nil {
+		t.Fatalf("load users: %v", err)
+	}
+}
+
+// assertSettled 检查游戏已全部结算：没有未完成的结算，每位用户恰好一笔 transactionType 交易，账本一致
+func (f *settlementFixture) assertSettled(t *testing.T, gameID, transactionType, status string) {
+	t.Helper()
+
+	if pending := f.users.PendingSettlements(); len(pending) != 0 {
+		t.Fatalf("got pending settlements %+v, want none", pending)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		count := 0
+		for _, transaction := range f.users.GetTransactions(id) {
+			if transaction.GameID == gameID && transaction.Type == transactionType {
+				count++
+			}
+		}
+		if count != 1 {
+			t.Fatalf("user %s has %d %s transactions, want 1", id, count, transactionType)
+		}
+		for _, userGame := range f.users.GetUserGames(id) {
+			if userGame.GameID == gameID && userGame.Status != status {
+				t.Fatalf("user %s game status %s, want %s", id, userGame.Status, status)
+			}
+		}
+	}
+
+	report, err := CheckLedger(f.games, f.users, f.ledger)
+	if err != nil {
+		t.Fatalf("check ledger: %v", err)
+	}
+	if !report.Consistent() {
+		t.Fatalf("ledger inconsistent: %+v", report)
+	}
+}
+
+func TestSettleGameRetriedAfterRestart(t *testing.T) {
+	f, g := newSettlementFixture(t)
+	if err := g.StartGame(); err != nil {
+		t.Fatalf("start game: %v", err)
+	}
+
+	// 第二位用户结算后保存失败，第三位用户尚未结算
+	f.store.failOn(storage.CollectionUsers, 2)
+	if err := g.ForceEnd(); err != nil {
+		t.Fatalf("force end: %v", err)
+	}
+
+	report, err := CheckLedger(f.games, f.users, f.ledger)
+	if err != nil {
+		t.Fatalf("check ledger: %v", err)
+	}
+	pending := false
+	for _, discrepancy := range report.Discrepancies {
+		if discrepancy.Account == ledger.EscrowAccount(g.ID) && discrepancy.Detail == "settlement pending" {
+			pending = true
+		}
+	}
+	if !pending {
+		t.Fatalf("pending settlement not reported: %+v", report.Discrepancies)
+	}
+
+	f.restart(t)
+	if got := f.users.PendingSettlements(); len(got) != 1 || got[0].GameID != g.ID {
+		t.Fatalf("got pending settlements %+v, want %s", got, g.ID)
+	}
+	if err := f.users.RetryPendingSettlements(); err != nil {
+		t.Fatalf("retry pending settlements: %v", err)
+	}
+	f.assertSettled(t, g.ID, user.TransactionSettle, user.UserGameFinished)
+
+	// 结算完成后再次重试不产生新的交易
+	if err := f.users.RetryPendingSettlements(); err != nil {
+		t.Fatalf("retry pending settlements: %v", err)
+	}
+	f.assertSettled(t, g.ID, user.TransactionSettle, user.UserGameFinished)
+}
+
+func TestRefundGameRetriedAfterLedgerFailure(t *testing.T) {
+	f, g := newSettlementFixture(t)
+
+	// 三笔转回托管账户的分录之后，第二笔退还分录写入失败
+	f.store.failOn(storage.CollectionLedger, 5)
+	if err := g.ForceEnd(); err != nil {
+		t.Fatalf("force end: %v", err)
+	}
+	if g.Status != game.StatusAbandoned {
+		t.Fatalf("got status %s, want %s", g.Status, game.StatusAbandoned)
+	}
+	if got := f.users.PendingSettlements(); len(got) != 1 {
+		t.Fatalf("got %d pending settlements, want 1", len(got))
+	}
+
+	if err := f.users.RetryPendingSettlements(); err != nil {
+		t.Fatalf("retry pending settlements: %v", err)
+	}
+	f.assertSettled(t, g.ID, user.TransactionRefund, user.UserGameRefunded)
+
+	f.restart(t)
+	f.assertSettled(t, g.ID, user.TransactionRefund, user.UserGameRefunded)
+}
+
+func TestSettleGameSavesBalanceWithGameStatus(t *testing.T) {
+	f, g := newSettlementFixture(t)
+	if err := g.StartGame(); err != nil {
+		t.Fatalf("start game: %v", err)
+	}
+
+	// 最后一位用户结算后保存失败，已保存的用户记录中余额和游戏记录必须一致
+	f.store.failOn(storage.CollectionUsers, 3)
+	if err := g.ForceEnd(); err != nil {
+		t.Fatalf("force end: %v", err)
+	}
+	balances := make(map[string]int)
+	for _, id := range []string{"a", "b"} {
+		u, err := f.users.GetUser(id)
+		if err != nil {
+			t.Fatalf("get user %s: %v", id, err)
+		}
+		balances[id] = u.Coins
+	}
+
+	f.restart(t)
+	for id, coins := range balances {
+		u, err := f.users.GetUser(id)
+		if err != nil {
+			t.Fatalf("get user %s: %v", id, err)
+		}
+		if u.Coins != coins {
+			t.Fatalf("user %s restored with %d coins, want %d", id, u.Coins, coins)
+		}
+	}
+
+	if err := f.users.RetryPendingSettlements(); err != nil {
+		t.Fatalf("retry pending settlements: %v", err)
+	}
+	f.assertSettled(t, g.ID, user.TransactionSettle, user.UserGameFinished)
+	for id, coins := range balances {
+		if u, _ := f.users.GetUser(id); u.Coins != coins {
+			t.Fatalf("user %s paid again on retry: got %d coins, want %d", id, u.Coins, coins)
+		}
+	}
+}
+
+func TestJoinAndSettingsUpdateAgreeOnBuyIn(t *testing.T) {
+	store := storage.NewMemoryStore()
+	um := user.NewManager(ledger.NewLedger(store), store)
+	gm := newTestManagerWithStore(t, store, &testClock{now: time.Unix(1000, 0)})
+	settlement := NewSettlement(gm, um)
+
+	for i := 0; i < 20; i++ {
+		gameID := fmt.Sprintf("g%d", i)
+		userID := fmt.Sprintf("u%d", i)
+		if err := um.CreateUser(&user.User{ID: userID, Name: userID, Coins: 10000}); err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		g, err := gm.CreateGame(gameID, "host", int64(i), game.DefaultSettings(), "small")
+		if err != nil {
+			t.Fatalf("create game: %v", err)
+		}
+		settings := g.GetSettings()
+		settings.BuyIn = 3000
+
+		// 同时加入游戏和修改买入金额，任一先完成时玩家托管的金额都与游戏的买入金额一致
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := settlement.Join(gameID, userID); err != nil {
+				t.Errorf("join: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := settlement.UpdateSettings(gameID, settings); err != nil && !errors.Is(err, utils.ErrInvalidInput) {
+				t.Errorf("update settings: %v", err)
+			}
+		}()
+		wg.Wait()
+
+		buyIn := g.GetSettings().BuyIn
+		coins := 0
+		g.View(func(g *game.Game) { coins = g.Players[userID].Coins })
+		u, err := um.GetUser(userID)
+		if err != nil {
+			t.Fatalf("get user: %v", err)
+		}
+		if coins != buyIn || u.Coins != 10000-buyIn {
+			t.Fatalf("game %s: player has %d coins and wallet %d, want buy-in %d escrowed", gameID, coins, u.Coins, buyIn)
+		}
+	}
+}
+
+func TestJoinKeepsWalletWhenEscrowSaveFails(t *testing.T) {
+	f, g := newSettlementFixture(t)
+	if err := f.users.CreateUser(&user.User{ID: "d", Name: "d", Coins: 10000}); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	f.store.failOn(storage.CollectionUsers, 1)
+	if _, err := f.settlement.Join(g.ID, "d"); !errors.Is(err, errStoreUnavailable) {
+		t.Fatalf("got error %v, want %v", err, errStoreUnavailable)
+	}
+	if _, joined := g.Players["d"]; joined {
+		t.Fatal("player joined although the escrow was not saved")
+	}
+
+	for _, restart := range []bool{false, true} {
+		if restart {
+			f.restart(t)
+		}
+		u, err := f.users.GetUser("d")
+		if err != nil {
+			t.Fatalf("get user: %v", err)
+		}
+		if u.Coins != 10000 || len(f.users.GetUserGames("d")) != 0 || len(f.users.GetTransactions("d")) != 1 {
+			t.Fatalf("restart %v: got coins %d, games %v and transactions %v, want the escrow undone",
+				restart, u.Coins, f.users.GetUserGames("d"), f.users.GetTransactions("d"))
+		}
+		report, err := CheckLedger(f.games, f.users, f.ledger)
+		if err != nil {
+			t.Fatalf("check ledger: %v", err)
+		}
+		if !report.Consistent() {
+			t.Fatalf("restart %v: ledger inconsistent: %+v", restart, report)
+		}
+	}
+
+	// 存储恢复后可以正常加入
+	if _, err := f.settlement.Join(g.ID, "d"); err != nil {
+		t.Fatalf("join after failure: %v", err)
+	}
+}
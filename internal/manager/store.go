@@ -0,0 +1,85 @@
+// internal/manager/store.go
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"monopoly/internal/game"
+	"monopoly/internal/storage"
+	"sort"
+	"strings"
+)
+
+// Load 从存储中的事件日志恢复所有游戏，进行中的游戏保留回合状态并可以继续进行
+// 事件日志损坏或缺失事件（写入失败）的游戏被跳过并记录日志，不影响其他游戏，只有读取存储失败时返回错误
+// 需在注册观察者和启动调度器之前调用
+func (gm *GameManager) Load() error {
+	records, err := gm.store.List(storage.CollectionEvents)
+	if err != nil {
+		return err
+	}
+
+	logs := make(map[string][]*game.Event)
+	invalid := make(map[string]error)
+	for key, raw := range records {
+		gameID, _, ok := cutLast(key, "/")
+		if !ok {
+			log.Printf("load games: skip invalid event key %q", key)
+			continue
+		}
+		event := &game.Event{}
+		if err := json.Unmarshal(raw, event); err != nil {
+			invalid[gameID] = fmt.Errorf("event %s: %w", key, err)
+			continue
+		}
+		logs[gameID] = append(logs[gameID], event)
+	}
+
+	gm.mutex.Lock()
+	defer gm.mutex.Unlock()
+
+	for gameID, err := range invalid {
+		gm.skip(gameID, err)
+		delete(logs, gameID)
+	}
+
+	for gameID, events := range logs {
+		sort.Slice(events, func(i, j int) bool { return events[i].Index < events[j].Index })
+
+		restored, err := game.Restore(events, gm.clock)
+		if err != nil {
+			gm.skip(gameID, err)
+			continue
+		}
+		for _, observer := range gm.observers {
+			restored.Observe(observer)
+		}
+		gm.games[gameID] = restored
+	}
+	return nil
+}
+
+// skip 跳过无法恢复的游戏，保留其ID避免新游戏与残留的事件日志混在一起，调用方需持有锁
+func (gm *GameManager) skip(gameID string, err error) {
+	log.Printf("load games: skip game %s: %v", gameID, err)
+	gm.skipped[gameID] = true
+}
+
+// saveEvent 将事件写入存储，作为观察者调用时持有游戏锁
+// 写入失败时只记录日志，之后的事件继续写入，日志中缺失的事件使该游戏在下次启动时被跳过
+func (gm *GameManager) saveEvent(g *game.Game, e *game.Event) {
+	key := fmt.Sprintf("%s/%08d", g.ID, e.Index)
+	if err := gm.store.Put(storage.CollectionEvents, key, e); err != nil {
+		log.Printf("store: game %s event %d: %v", g.ID, e.Index, err)
+	}
+}
+
+// cutLast 在最后一个分隔符处切分字符串
+func cutLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
@@ -0,0 +1,257 @@
+// internal/storage/file.go
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	logFileName      = "store.log"
+	snapshotFileName = "snapshot.json"
+
+	// DefaultSnapshotEvery 默认每追加多少条日志写一次快照
+	DefaultSnapshotEvery = 1000
+)
+
+// 日志记录的操作类型
+const (
+	opPut    = "put"
+	opDelete = "delete"
+)
+
+// logRecord 追加日志中的一条变更
+type logRecord struct {
+	Op         string          `json:"op"`
+	Collection string          `json:"collection"`
+	Key        string          `json:"key"`
+	Value      json.RawMessage `json:"value,omitempty"`
+}
+
+// FileStore 文件存储
+// 每次变更追加到数据目录下的日志文件并同步到磁盘，日志达到 snapshotEvery 条后将全部数据写入快照并清空日志；
+// 启动时先读取快照再重放日志，末尾写入不完整的记录会被丢弃
+type FileStore struct {
+	dir           string
+	memory        *MemoryStore
+	log           *os.File
+	logged        int
+	snapshotEvery int
+	mutex         sync.Mutex
+}
+
+// OpenFileStore 打开数据目录中的文件存储，目录不存在时自动创建
+// snapshotEvery 不大于0时使用 DefaultSnapshotEvery
+func OpenFileStore(dir string, snapshotEvery int) (*FileStore, error) {
+	if snapshotEvery <= 0 {
+		snapshotEvery = DefaultSnapshotEvery
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &FileStore{
+		dir:           dir,
+		memory:        NewMemoryStore(),
+		snapshotEvery: snapshotEvery,
+	}
+	if err := s.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := s.replayLog(); err != nil {
+		return nil, err
+	}
+
+	log, err := os.OpenFile(s.path(logFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	s.log = log
+	return s, nil
+}
+
+// Put 写入或覆盖一条记录
+func (s *FileStore) Put(collection, key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.append(logRecord{Op: opPut, Collection: collection, Key: key, Value: raw})
+}
+
+// Delete 删除一条记录
+func (s *FileStore) Delete(collection, key string) error {
+	return s.append(logRecord{Op: opDelete, Collection: collection, Key: key})
+}
+
+// List 读取集合中的全部记录
+func (s *FileStore) List(collection string) (map[string]json.RawMessage, error) {
+	return s.memory.List(collection)
+}
+
+// Snapshot 立即将全部数据写入快照并清空日志
+func (s *FileStore) Snapshot() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.snapshot()
+}
+
+// Close 写入最终快照并关闭日志文件
+func (s *FileStore) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.log == nil {
+		return nil
+	}
+
+	err := s.snapshot()
+	if closeErr := s.log.Close(); err == nil {
+		err = closeErr
+	}
+	s.log = nil
+	return err
+}
+
+// append 将变更写入日志后应用到内存，日志条数达到阈值时写入快照
+func (s *FileStore) append(record logRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.log == nil {
+		return os.ErrClosed
+	}
+	if _, err := s.log.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := s.log.Sync(); err != nil {
+		return err
+	}
+
+	s.apply(record)
+	s.logged++
+	if s.logged >= s.snapshotEvery {
+		return s.snapshot()
+	}
+	return nil
+}
+
+// apply 将一条变更应用到内存数据
+func (s *FileStore) apply(record logRecord) {
+	s.memory.mutex.Lock()
+	defer s.memory.mutex.Unlock()
+
+	switch record.Op {
+	case opPut:
+		s.memory.put(record.Collection, record.Key, record.Value)
+	case opDelete:
+		delete(s.memory.data[record.Collection], record.Key)
+	}
+}
+
+// snapshot 先写临时文件再原子替换快照，随后清空日志，调用方需持有锁
+// 替换快照后、清空日志前崩溃时，重放日志只会重复写入相同的数据
+func (s *FileStore) snapshot() error {
+	s.memory.mutex.RLock()
+	data, err := json.Marshal(s.memory.data)
+	s.memory.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path(snapshotFileName + ".tmp")
+	if err := writeFileSync(tmp, data); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path(snapshotFileName)); err != nil {
+		return err
+	}
+
+	if err := s.log.Truncate(0); err != nil {
+		return err
+	}
+	s.logged = 0
+	return nil
+}
+
+// loadSnapshot 读取快照，快照不存在时从空数据开始
+func (s *FileStore) loadSnapshot() error {
+	data, err := os.ReadFile(s.path(snapshotFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &s.memory.data)
+}
+
+// replayLog 按顺序重放日志，截断末尾不完整的记录
+func (s *FileStore) replayLog() error {
+	file, err := os.OpenFile(s.path(logFileName), os.O_RDWR, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(bytes.TrimSpace(line)) > 0 {
+				// 写入中途崩溃留下的不完整记录
+				return file.Truncate(offset)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var record logRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return file.Truncate(offset)
+		}
+		s.apply(record)
+		s.logged++
+		offset += int64(len(line))
+	}
+}
+
+// path 获取数据目录中的文件路径
+func (s *FileStore) path(name string) string {
+	return filepath.Join(s.dir, name)
+}
+
+// writeFileSync 写入文件并同步到磁盘
+func writeFileSync(path string, data []byte) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,186 @@
+// internal/storage/file_test.go
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// openTestStore 打开数据目录中的文件存储，测试结束时关闭
+func openTestStore(t *testing.T, dir string, snapshotEvery int) *FileStore {
+	t.Helper()
+
+	s, err := OpenFileStore(dir, snapshotEvery)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// mustPut 写入一条记录
+func mustPut(t *testing.T, s Store, collection, key string, value interface{}) {
+	t.Helper()
+
+	if err := s.Put(collection, key, value); err != nil {
+		t.Fatalf("put %s/%s: %v", collection, key, err)
+	}
+}
+
+// assertRecords 检查集合中恰好有 want 中的记录
+func assertRecords(t *testing.T, s Store, collection string, want map[string]string) {
+	t.Helper()
+
+	records, err := s.List(collection)
+	if err != nil {
+		t.Fatalf("list %s: %v", collection, err)
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %d records in %s, want %d: %v", len(records), collection, len(want), records)
+	}
+	for key, value := range want {
+		if got := string(records[key]); got != value {
+			t.Fatalf("%s/%s: got %s, want %s", collection, key, got, value)
+		}
+	}
+}
+
+// logLines 统计日志文件中的记录条数
+func logLines(t *testing.T, dir string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, logFileName))
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	lines := 0
+	for _, b := range data {
+		if b == '\n' {
+			lines++
+		}
+	}
+	return lines
+}
+
+// appendLog 向日志文件末尾追加原始数据，模拟写入中途崩溃
+func appendLog(t *testing.T, dir, data string) {
+	t.Helper()
+
+	file, err := os.OpenFile(filepath.Join(dir, logFileName), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("open log: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(data); err != nil {
+		t.Fatalf("append log: %v", err)
+	}
+}
+
+func TestFileStoreReplaysLogAfterCrash(t *testing.T) {
+	dir := t.TempDir()
+	s := openTestStore(t, dir, 100)
+	mustPut(t, s, CollectionUsers, "a", 1)
+	mustPut(t, s, CollectionUsers, "b", 2)
+	mustPut(t, s, CollectionUsers, "a", 3)
+	if err := s.Delete(CollectionUsers, "b"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	mustPut(t, s, CollectionEvents, "g1/00000001", "x")
+
+	// 不关闭存储，直接从磁盘重新打开
+	reopened := openTestStore(t, dir, 100)
+	assertRecords(t, reopened, CollectionUsers, map[string]string{"a": "3"})
+	assertRecords(t, reopened, CollectionEvents, map[string]string{"g1/00000001": `"x"`})
+}
+
+func TestFileStoreReplaysLogOnTopOfSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	s := openTestStore(t, dir, 2)
+	mustPut(t, s, CollectionUsers, "a", 1)
+	mustPut(t, s, CollectionUsers, "b", 2) // 第二条写入后生成快照并清空日志
+	mustPut(t, s, CollectionUsers, "a", 3)
+	if err := s.Delete(CollectionUsers, "b"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	mustPut(t, s, CollectionUsers, "c", 4)
+
+	if _, err := os.Stat(filepath.Join(dir, snapshotFileName)); err != nil {
+		t.Fatalf("snapshot not written: %v", err)
+	}
+	if lines := logLines(t, dir); lines != 1 {
+		t.Fatalf("got %d log records after snapshots, want 1", lines)
+	}
+
+	reopened := openTestStore(t, dir, 2)
+	assertRecords(t, reopened, CollectionUsers, map[string]string{"a": "3", "c": "4"})
+}
+
+func TestFileStoreReplaysLogLeftBySnapshotCrash(t *testing.T) {
+	dir := t.TempDir()
+	s := openTestStore(t, dir, 100)
+	mustPut(t, s, CollectionUsers, "a", 1)
+	mustPut(t, s, CollectionUsers, "b", 2)
+	log, err := os.ReadFile(filepath.Join(dir, logFileName))
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+
+	// 快照已替换但日志尚未清空时崩溃
+	if err := s.Snapshot(); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	appendLog(t, dir, string(log))
+
+	reopened := openTestStore(t, dir, 100)
+	assertRecords(t, reopened, CollectionUsers, map[string]string{"a": "1", "b": "2"})
+}
+
+func TestFileStoreDropsTruncatedLastRecord(t *testing.T) {
+	tests := []struct {
+		name string
+		tail string
+	}{
+		{"partial line", `{"op":"put","collection":"users","key":"c","val`},
+		{"corrupt line", "{\"op\":\"put\",\"coll\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			s := openTestStore(t, dir, 100)
+			mustPut(t, s, CollectionUsers, "a", 1)
+			mustPut(t, s, CollectionUsers, "b", 2)
+			appendLog(t, dir, tt.tail)
+
+			reopened := openTestStore(t, dir, 100)
+			assertRecords(t, reopened, CollectionUsers, map[string]string{"a": "1", "b": "2"})
+			if lines := logLines(t, dir); lines != 2 {
+				t.Fatalf("got %d log records, want the incomplete record truncated", lines)
+			}
+
+			// 截断后追加的记录不会与残留数据拼接
+			mustPut(t, reopened, CollectionUsers, "c", 3)
+			again := openTestStore(t, dir, 100)
+			assertRecords(t, again, CollectionUsers, map[string]string{"a": "1", "b": "2", "c": "3"})
+		})
+	}
+}
+
+func TestFileStoreRejectsWritesAfterClose(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenFileStore(dir, 100)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	mustPut(t, s, CollectionUsers, "a", 1)
+	if err := s.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if err := s.Put(CollectionUsers, "b", 2); err == nil {
+		t.Fatal("put succeeded on a closed store")
+	}
+
+	reopened := openTestStore(t, dir, 100)
+	assertRecords(t, reopened, CollectionUsers, map[string]string{"a": "1"})
+}
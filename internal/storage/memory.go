@@ -0,0 +1,70 @@
+// internal/storage/memory.go
+package storage
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// MemoryStore 内存存储，进程退出后数据丢失，用于测试和未指定数据目录时
+type MemoryStore struct {
+	data  map[string]map[string]json.RawMessage
+	mutex sync.RWMutex
+}
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		data: make(map[string]map[string]json.RawMessage),
+	}
+}
+
+// Put 写入或覆盖一条记录
+func (s *MemoryStore) Put(collection, key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.put(collection, key, raw)
+	return nil
+}
+
+// Delete 删除一条记录
+func (s *MemoryStore) Delete(collection, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.data[collection], key)
+	return nil
+}
+
+// List 读取集合中的全部记录
+func (s *MemoryStore) List(collection string) (map[string]json.RawMessage, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	records := make(map[string]json.RawMessage, len(s.data[collection]))
+	for key, raw := range s.data[collection] {
+		records[key] = raw
+	}
+	return records, nil
+}
+
+// Close 内存存储无需释放资源
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// put 写入已序列化的记录，调用方需持有锁
+func (s *MemoryStore) put(collection, key string, raw json.RawMessage) {
+	records, exists := s.data[collection]
+	if !exists {
+		records = make(map[string]json.RawMessage)
+		s.data[collection] = records
+	}
+	records[key] = raw
+}
@@ -0,0 +1,30 @@
+// internal/storage/storage.go
+package storage
+
+import (
+	"encoding/json"
+)
+
+// 各管理器使用的集合名称
+const (
+	CollectionUsers        = "users"
+	CollectionTransactions = "transactions"
+	CollectionLedger       = "ledger"
+	CollectionEvents       = "events"
+	CollectionCredentials  = "credentials"
+	CollectionAudit        = "audit"
+	CollectionSettlements  = "settlements"
+)
+
+// Store 持久化存储接口，数据按集合和键组织，值以 JSON 保存
+// 用户管理器、账本和游戏管理器在每次变更后写入，启动时从中恢复
+type Store interface {
+	// Put 写入或覆盖一条记录
+	Put(collection, key string, value interface{}) error
+	// Delete 删除一条记录，记录不存在时不报错
+	Delete(collection, key string) error
+	// List 读取集合中的全部记录
+	List(collection string) (map[string]json.RawMessage, error)
+	// Close 关闭存储并释放资源
+	Close() error
+}
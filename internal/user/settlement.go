@@ -0,0 +1,299 @@
+// internal/user/settlement.go
+package user
+
+import (
+	"errors"
+	"monopoly/internal/ledger"
+	"monopoly/internal/storage"
+	"monopoly/pkg/utils"
+	"sort"
+	"time"
+)
+
+// 交易类型
+const (
+	TransactionAdd    = "add"    // 充值
+	TransactionDeduct = "deduct" // 扣除
+	TransactionEscrow = "escrow" // 加入游戏时托管买入金额
+	TransactionSettle = "settle" // 游戏结束时返还最终金币
+	TransactionRefund = "refund" // 离开或放弃游戏时退还买入金额
+)
+
+// 用户游戏记录的参与状态
+const (
+	UserGameActive   = "active"   // 已托管买入金额，等待结算
+	UserGameFinished = "finished" // 游戏结束，已结算
+	UserGameRefunded = "refunded" // 离开或游戏被放弃，已退还
+)
+
+// GameResult 一位用户在游戏结束时的结算结果
+type GameResult struct {
+	UserID string `json:"userId"`
+	Coins  int    `json:"coins"` // 返还钱包的最终金币
+	Rank   int    `json:"rank"`
+}
+
+// PendingSettlement 一局游戏中所有用户的批量结算（游戏结束返还或放弃退还）
+// 结算前先整体写入存储，全部用户完成后删除；中途失败时保留，启动时重试，重试只结算尚未完成的用户
+type PendingSettlement struct {
+	GameID       string         `json:"gameId"`
+	Status       string         `json:"status"`       // 结算后游戏记录的状态，见 UserGame* 常量
+	Transactions []Transaction  `json:"transactions"` // 每位用户的结算交易，交易ID在重试时保持不变
+	Ranks        map[string]int `json:"ranks,omitempty"`
+	EndTime      time.Time      `json:"endTime"`
+}
+
+// EscrowBuyIn 从用户钱包托管买入金额并记录游戏参与
+// 余额、交易和游戏记录保存失败时撤销托管，钱包余额保持不变
+func (m *Manager) EscrowBuyIn(userID, gameID string, amount int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	user, exists := m.users[userID]
+	if !exists {
+		return utils.ErrUserNotFound
+	}
+
+	if m.activeGame(userID, gameID) != nil {
+		return utils.ErrPlayerExists
+	}
+
+	if amount <= 0 {
+		return utils.ErrInvalidInput
+	}
+
+	if user.Coins < amount {
+		return utils.ErrInsufficientFunds
+	}
+
+	transaction := newTransaction(user.ID, TransactionEscrow, gameID, amount, time.Now())
+	if err := m.recordTransaction(transaction); err != nil {
+		return err
+	}
+	m.recordGameParticipation(userID, gameID, amount)
+	if err := m.saveTransaction(transaction); err != nil {
+		if cancelErr := m.cancelEscrow(userID, gameID); cancelErr != nil {
+			return errors.Join(err, cancelErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// CancelEscrow 撤销尚未生效的托管，恢复金币并删除对应的交易和游戏记录
+// 用于加入游戏失败时回滚 EscrowBuyIn，账本只追加不删除，因此记入一笔冲销分录
+func (m *Manager) CancelEscrow(userID, gameID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.cancelEscrow(userID, gameID)
+}
+
+// cancelEscrow 撤销托管，调用方需持有锁
+func (m *Manager) cancelEscrow(userID, gameID string) error {
+	userGame := m.activeGame(userID, gameID)
+	if userGame == nil {
+		return utils.ErrGameNotFound
+	}
+
+	if err := m.ledger.Record(ledger.Entry{
+		Debit:  ledger.UserAccount(userID),
+		Credit: ledger.EscrowAccount(gameID),
+		Amount: userGame.BuyIn,
+		Reason: ledger.ReasonEscrowCancelled,
+		GameID: gameID,
+	}); err != nil {
+		return err
+	}
+	m.users[userID].Coins += userGame.BuyIn
+
+	games := m.userGames[userID]
+	for i := len(games) - 1; i >= 0; i-- {
+		if games[i].GameID == gameID && games[i].Status == UserGameActive {
+			m.userGames[userID] = append(games[:i], games[i+1:]...)
+			break
+		}
+	}
+
+	transactions := m.transactions[userID]
+	for i := len(transactions) - 1; i >= 0; i-- {
+		if transactions[i].GameID == gameID && transactions[i].Type == TransactionEscrow {
+			if err := m.store.Delete(storage.CollectionTransactions, transactions[i].ID); err != nil {
+				return err
+			}
+			m.transactions[userID] = append(transactions[:i], transactions[i+1:]...)
+			break
+		}
+	}
+	return m.saveUser(userID)
+}
+
+// RefundBuyIn 退还托管的买入金额，用于玩家在开始前离开游戏
+func (m *Manager) RefundBuyIn(userID, gameID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	userGame := m.activeGame(userID, gameID)
+	if userGame == nil {
+		return utils.ErrGameNotFound
+	}
+
+	return m.refund(userGame)
+}
+
+// RefundGame 退还一局被放弃的游戏中所有用户托管的买入金额
+func (m *Manager) RefundGame(gameID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	pending := &PendingSettlement{
+		GameID:       gameID,
+		Status:       UserGameRefunded,
+		Transactions: make([]Transaction, 0),
+		EndTime:      now,
+	}
+	for userID := range m.userGames {
+		if userGame := m.activeGame(userID, gameID); userGame != nil {
+			pending.Transactions = append(pending.Transactions, newTransaction(userID, TransactionRefund, gameID, userGame.BuyIn, now))
+		}
+	}
+	if len(pending.Transactions) == 0 {
+		return nil
+	}
+	sort.Slice(pending.Transactions, func(i, j int) bool {
+		return pending.Transactions[i].UserID < pending.Transactions[j].UserID
+	})
+
+	return m.settle(pending)
+}
+
+// SettleGame 将游戏结束时的最终金币返还用户钱包，并记录名次和结束时间
+// 所有用户都有待结算的记录时才会执行，否则不做任何修改
+func (m *Manager) SettleGame(gameID string, results []GameResult) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, result := range results {
+		if m.activeGame(result.UserID, gameID) == nil {
+			return utils.ErrGameNotFound
+		}
+		if result.Coins < 0 {
+			return utils.ErrInvalidInput
+		}
+	}
+
+	now := time.Now()
+	pending := &PendingSettlement{
+		GameID:       gameID,
+		Status:       UserGameFinished,
+		Transactions: make([]Transaction, 0, len(results)),
+		Ranks:        make(map[string]int, len(results)),
+		EndTime:      now,
+	}
+	for _, result := range results {
+		pending.Transactions = append(pending.Transactions, newTransaction(result.UserID, TransactionSettle, gameID, result.Coins, now))
+		pending.Ranks[result.UserID] = result.Rank
+	}
+
+	return m.settle(pending)
+}
+
+// RetryPendingSettlements 重试上次未完成的结算，需在 Load 之后调用
+// 所有结算都会尝试一次，返回遇到的全部错误
+func (m *Manager) RetryPendingSettlements() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	gameIDs := make([]string, 0, len(m.pending))
+	for gameID := range m.pending {
+		gameIDs = append(gameIDs, gameID)
+	}
+	sort.Strings(gameIDs)
+
+	var errs []error
+	for _, gameID := range gameIDs {
+		if err := m.completeSettlement(m.pending[gameID]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PendingSettlements 获取尚未完成的结算，按游戏ID排序
+func (m *Manager) PendingSettlements() []PendingSettlement {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	settlements := make([]PendingSettlement, 0, len(m.pending))
+	for _, pending := range m.pending {
+		settlements = append(settlements, *pending)
+	}
+	sort.Slice(settlements, func(i, j int) bool { return settlements[i].GameID < settlements[j].GameID })
+	return settlements
+}
+
+// settle 保存结算记录后逐个结算用户，保存失败时不做任何修改，调用方需持有锁
+func (m *Manager) settle(pending *PendingSettlement) error {
+	if err := m.store.Put(storage.CollectionSettlements, pending.GameID, pending); err != nil {
+		return err
+	}
+	m.pending[pending.GameID] = pending
+	return m.completeSettlement(pending)
+}
+
+// completeSettlement 结算记录中尚未结算的用户，全部完成后删除结算记录，调用方需持有锁
+// 余额和游戏记录在同一次写入中保存，游戏记录已关闭的用户在之前的尝试中已经入账，
+// 只重新保存交易记录，因此可以安全地重复调用
+func (m *Manager) completeSettlement(pending *PendingSettlement) error {
+	for _, transaction := range pending.Transactions {
+		if _, exists := m.users[transaction.UserID]; !exists {
+			continue
+		}
+
+		if userGame := m.activeGame(transaction.UserID, pending.GameID); userGame != nil {
+			if err := m.recordTransaction(transaction); err != nil {
+				return err
+			}
+			userGame.Status = pending.Status
+			userGame.EndTime = pending.EndTime
+			userGame.FinalRank = pending.Ranks[transaction.UserID]
+		}
+
+		if err := m.saveTransaction(transaction); err != nil {
+			return err
+		}
+	}
+
+	if err := m.store.Delete(storage.CollectionSettlements, pending.GameID); err != nil {
+		return err
+	}
+	delete(m.pending, pending.GameID)
+	return nil
+}
+
+// refund 退还托管金额并关闭游戏记录，调用方需持有锁
+func (m *Manager) refund(userGame *UserGame) error {
+	if err := m.applyTransaction(userGame.UserID, TransactionRefund, userGame.GameID, userGame.BuyIn); err != nil {
+		return err
+	}
+
+	userGame.Status = UserGameRefunded
+	userGame.EndTime = time.Now()
+	return m.saveUser(userGame.UserID)
+}
+
+// activeGame 查找用户在指定游戏中待结算的记录，调用方需持有锁
+func (m *Manager) activeGame(userID, gameID string) *UserGame {
+	if _, exists := m.users[userID]; !exists {
+		return nil
+	}
+
+	games := m.userGames[userID]
+	for i := range games {
+		if games[i].GameID == gameID && games[i].Status == UserGameActive {
+			return &games[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// internal/user/store.go
+package user
+
+import (
+	"encoding/json"
+	"monopoly/internal/storage"
+	"sort"
+)
+
+// userRecord 用户在存储中的记录，余额和游戏记录在同一次写入中保存
+// 结算时返还的金币和关闭的游戏记录因此同时生效，重试结算不会重复入账
+type userRecord struct {
+	User
+	Games []UserGame `json:"games"`
+}
+
+// Load 从存储恢复用户、交易记录、游戏记录和未完成的结算，需在处理请求之前调用
+func (m *Manager) Load() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	users, err := m.store.List(storage.CollectionUsers)
+	if err != nil {
+		return err
+	}
+	for id, raw := range users {
+		var record userRecord
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return err
+		}
+		if record.Role == "" {
+			// 引入角色之前创建的用户
+			record.Role = RolePlayer
+		}
+		if record.Games == nil {
+			record.Games = make([]UserGame, 0)
+		}
+		user := record.User
+		m.users[id] = &user
+		m.transactions[id] = make([]Transaction, 0)
+		m.userGames[id] = record.Games
+	}
+
+	transactions, err := m.store.List(storage.CollectionTransactions)
+	if err != nil {
+		return err
+	}
+	for _, raw := range transactions {
+		var transaction Transaction
+		if err := json.Unmarshal(raw, &transaction); err != nil {
+			return err
+		}
+		m.transactions[transaction.UserID] = append(m.transactions[transaction.UserID], transaction)
+	}
+	for _, list := range m.transactions {
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].Timestamp.Before(list[j].Timestamp)
+		})
+	}
+
+	settlements, err := m.store.List(storage.CollectionSettlements)
+	if err != nil {
+		return err
+	}
+	for gameID, raw := range settlements {
+		var pending PendingSettlement
+		if err := json.Unmarshal(raw, &pending); err != nil {
+			return err
+		}
+		m.pending[gameID] = &pending
+	}
+	return nil
+}
+
+// saveUser 在一次写入中保存用户信息及其游戏记录，调用方需持有锁
+func (m *Manager) saveUser(userID string) error {
+	games := m.userGames[userID]
+	if games == nil {
+		games = make([]UserGame, 0)
+	}
+	return m.store.Put(storage.CollectionUsers, userID, userRecord{User: *m.users[userID], Games: games})
+}
+
+// deleteUser 从存储删除用户及其全部记录，调用方需持有锁
+func (m *Manager) deleteUser(userID string, transactions []Transaction) error {
+	for _, transaction := range transactions {
+		if err := m.store.Delete(storage.CollectionTransactions, transaction.ID); err != nil {
+			return err
+		}
+	}
+	return m.store.Delete(storage.CollectionUsers, userID)
+}
@@ -2,16 +2,25 @@
 package user
 
 import (
+	"monopoly/internal/ledger"
+	"monopoly/internal/storage"
 	"monopoly/pkg/utils"
 	"sync"
 	"time"
 )
 
+// 用户角色，游戏的主持人不是用户角色，而是创建该游戏的用户，只能管理自己主持的游戏
+const (
+	RolePlayer = "player" // 普通玩家，新用户的默认角色
+	RoleAdmin  = "admin"  // 管理员，可使用管理接口
+)
+
 // User 用户基本信息
 type User struct {
 	ID       string    `json:"id"`
 	Name     string    `json:"name"`
 	Coins    int       `json:"coins"`
+	Role     string    `json:"role"` // 用户角色，见 Role* 常量
 	CreateAt time.Time `json:"createAt"`
 }
 
@@ -19,7 +28,8 @@ type User struct {
 type Transaction struct {
 	ID        string    `json:"id"`
 	UserID    string    `json:"userId"`
-	Type      string    `json:"type"` // "add" or "deduct"
+	Type      string    `json:"type"`             // 交易类型，见 Transaction* 常量
+	GameID    string    `json:"gameId,omitempty"` // 托管、结算和退还交易对应的游戏
 	Amount    int       `json:"amount"`
 	Timestamp time.Time `json:"timestamp"`
 }
@@ -31,6 +41,8 @@ type UserGame struct {
 	JoinTime  time.Time `json:"joinTime"`
 	EndTime   time.Time `json:"endTime,omitempty"`
 	FinalRank int       `json:"finalRank,omitempty"`
+	BuyIn     int       `json:"buyIn"`  // 加入时托管的买入金额
+	Status    string    `json:"status"` // 参与状态，见 UserGame* 常量
 }
 
 // Manager 用户管理器
@@ -38,15 +50,21 @@ type Manager struct {
 	users        map[string]*User
 	transactions map[string][]Transaction
 	userGames    map[string][]UserGame
+	pending      map[string]*PendingSettlement // 按游戏ID索引的未完成结算
+	ledger       *ledger.Ledger
+	store        storage.Store
 	mutex        sync.RWMutex
 }
 
-// NewManager 创建新的用户管理器
-func NewManager() *Manager {
+// NewManager 创建新的用户管理器，钱包余额的每次变动都记入账本，所有变更写入存储
+func NewManager(l *ledger.Ledger, store storage.Store) *Manager {
 	return &Manager{
 		users:        make(map[string]*User),
 		transactions: make(map[string][]Transaction),
 		userGames:    make(map[string][]UserGame),
+		pending:      make(map[string]*PendingSettlement),
+		ledger:       l,
+		store:        store,
 	}
 }
 
@@ -59,12 +77,23 @@ func (m *Manager) CreateUser(user *User) error {
 		return utils.ErrUserExists
 	}
 
+	if user.Coins < 0 {
+		return utils.ErrInvalidInput
+	}
+
+	// 初始金币作为一笔充值记入交易和账本
+	coins := user.Coins
+	user.Coins = 0
+	user.Role = RolePlayer
 	user.CreateAt = time.Now()
 	m.users[user.ID] = user
 	m.transactions[user.ID] = make([]Transaction, 0)
 	m.userGames[user.ID] = make([]UserGame, 0)
 
-	return nil
+	if coins == 0 {
+		return m.saveUser(user.ID)
+	}
+	return m.applyTransaction(user.ID, TransactionAdd, "", coins)
 }
 
 // GetUser 获取用户信息
@@ -80,17 +109,47 @@ func (m *Manager) GetUser(id string) (*User, error) {
 	return user, nil
 }
 
-// UpdateUser 更新用户信息
+// UpdateUser 更新用户信息，金币只能通过交易变动，角色只能通过 SetRole 修改，更新时保留原有余额和角色
 func (m *Manager) UpdateUser(user *User) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	if _, exists := m.users[user.ID]; !exists {
+	existing, exists := m.users[user.ID]
+	if !exists {
 		return utils.ErrUserNotFound
 	}
 
+	user.Coins = existing.Coins
+	user.Role = existing.Role
 	m.users[user.ID] = user
-	return nil
+	return m.saveUser(user.ID)
+}
+
+// SetRole 设置用户角色
+func (m *Manager) SetRole(userID, role string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	user, exists := m.users[userID]
+	if !exists {
+		return utils.ErrUserNotFound
+	}
+
+	if role != RolePlayer && role != RoleAdmin {
+		return utils.ErrInvalidRole
+	}
+
+	user.Role = role
+	return m.saveUser(userID)
+}
+
+// IsAdmin 判断用户是否为管理员，用户不存在时返回 false
+func (m *Manager) IsAdmin(userID string) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	user, exists := m.users[userID]
+	return exists && user.Role == RoleAdmin
 }
 
 // DeleteUser 删除用户
@@ -98,15 +157,34 @@ func (m *Manager) DeleteUser(id string) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	if _, exists := m.users[id]; !exists {
+	user, exists := m.users[id]
+	if !exists {
 		return utils.ErrUserNotFound
 	}
 
+	// 仍有托管金币的用户不能删除，否则游戏结束时无法结算
+	for _, userGame := range m.userGames[id] {
+		if userGame.Status == UserGameActive {
+			return utils.ErrUserInGame
+		}
+	}
+
+	// 剩余余额转出系统，账本中的用户账户归零
+	if err := m.ledger.Record(ledger.Entry{
+		Debit:  ledger.AccountExternal,
+		Credit: ledger.UserAccount(id),
+		Amount: user.Coins,
+		Reason: ledger.ReasonAccountClosed,
+	}); err != nil {
+		return err
+	}
+
+	transactions := m.transactions[id]
 	delete(m.users, id)
 	delete(m.transactions, id)
 	delete(m.userGames, id)
 
-	return nil
+	return m.deleteUser(id, transactions)
 }
 
 // AddCoins 添加游戏币
@@ -119,19 +197,11 @@ func (m *Manager) AddCoins(userID string, amount int) error {
 		return utils.ErrUserNotFound
 	}
 
-	user.Coins += amount
-
-	// 记录交易
-	transaction := Transaction{
-		ID:        utils.GenerateID(), // 假设utils包中有这个函数
-		UserID:    userID,
-		Type:      "add",
-		Amount:    amount,
-		Timestamp: time.Now(),
+	if amount <= 0 {
+		return utils.ErrInvalidInput
 	}
-	m.transactions[userID] = append(m.transactions[userID], transaction)
 
-	return nil
+	return m.applyTransaction(user.ID, TransactionAdd, "", amount)
 }
 
 // DeductCoins 扣除游戏币
@@ -144,23 +214,15 @@ func (m *Manager) DeductCoins(userID string, amount int) error {
 		return utils.ErrUserNotFound
 	}
 
-	if user.Coins < amount {
-		return utils.ErrInsufficientFunds
+	if amount <= 0 {
+		return utils.ErrInvalidInput
 	}
 
-	user.Coins -= amount
-
-	// 记录交易
-	transaction := Transaction{
-		ID:        utils.GenerateID(),
-		UserID:    userID,
-		Type:      "deduct",
-		Amount:    amount,
-		Timestamp: time.Now(),
+	if user.Coins < amount {
+		return utils.ErrInsufficientFunds
 	}
-	m.transactions[userID] = append(m.transactions[userID], transaction)
 
-	return nil
+	return m.applyTransaction(user.ID, TransactionDeduct, "", amount)
 }
 
 // GetTransactions 获取用户交易记录
@@ -183,14 +245,8 @@ func (m *Manager) RecordGameParticipation(userID string, gameID string) error {
 		return utils.ErrUserNotFound
 	}
 
-	userGame := UserGame{
-		GameID:   gameID,
-		UserID:   userID,
-		JoinTime: time.Now(),
-	}
-	m.userGames[userID] = append(m.userGames[userID], userGame)
-
-	return nil
+	m.recordGameParticipation(userID, gameID, 0)
+	return m.saveUser(userID)
 }
 
 // GetUserGames 获取用户参与的游戏记录
@@ -214,9 +270,120 @@ func (m *Manager) UpdateGameResult(userID string, gameID string, rank int) error
 		if games[i].GameID == gameID {
 			games[i].EndTime = time.Now()
 			games[i].FinalRank = rank
-			return nil
+			return m.saveUser(userID)
 		}
 	}
 
 	return utils.ErrGameNotFound
 }
+
+// ReconcileLedger 将所有用户的钱包余额与账本比对，已删除用户的账户余额应为0
+func (m *Manager) ReconcileLedger() []ledger.Discrepancy {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	actual := make(map[ledger.Account]int)
+	for _, account := range m.ledger.Accounts(ledger.UserAccountPrefix) {
+		actual[account] = 0
+	}
+	for id, user := range m.users {
+		actual[ledger.UserAccount(id)] = user.Coins
+	}
+	return m.ledger.Reconcile(actual, nil)
+}
+
+// applyTransaction 按交易类型变动用户余额，追加交易记录并记入账本
+// 调用方需持有锁，并已验证用户存在且余额充足
+func (m *Manager) applyTransaction(userID, transactionType, gameID string, amount int) error {
+	transaction := newTransaction(userID, transactionType, gameID, amount, time.Now())
+	if err := m.recordTransaction(transaction); err != nil {
+		return err
+	}
+	return m.saveTransaction(transaction)
+}
+
+// newTransaction 创建交易记录
+func newTransaction(userID, transactionType, gameID string, amount int, timestamp time.Time) Transaction {
+	return Transaction{
+		ID:        utils.GenerateID(),
+		UserID:    userID,
+		Type:      transactionType,
+		GameID:    gameID,
+		Amount:    amount,
+		Timestamp: timestamp,
+	}
+}
+
+// recordTransaction 将交易记入账本并更新内存中的余额和交易记录，出错时不做任何修改
+// 账本中已有该交易的分录时不再重复记账，用于重试未完成的结算，调用方需持有锁
+func (m *Manager) recordTransaction(transaction Transaction) error {
+	entry := ledger.Entry{
+		Amount:        transaction.Amount,
+		Reason:        ledger.Reason(transaction.Type),
+		GameID:        transaction.GameID,
+		TransactionID: transaction.ID,
+		Timestamp:     transaction.Timestamp,
+	}
+	userID := transaction.UserID
+	delta := transaction.Amount
+	switch transaction.Type {
+	case TransactionAdd:
+		entry.Debit, entry.Credit = ledger.UserAccount(userID), ledger.AccountExternal
+	case TransactionDeduct:
+		entry.Debit, entry.Credit = ledger.AccountExternal, ledger.UserAccount(userID)
+		delta = -transaction.Amount
+	case TransactionEscrow:
+		entry.Debit, entry.Credit = ledger.EscrowAccount(transaction.GameID), ledger.UserAccount(userID)
+		delta = -transaction.Amount
+	case TransactionSettle, TransactionRefund:
+		entry.Debit, entry.Credit = ledger.UserAccount(userID), ledger.EscrowAccount(transaction.GameID)
+	default:
+		return utils.ErrInvalidInput
+	}
+
+	if !m.recorded(transaction) {
+		if err := m.ledger.Record(entry); err != nil {
+			return err
+		}
+	}
+
+	m.users[userID].Coins += delta
+	for _, existing := range m.transactions[userID] {
+		if existing.ID == transaction.ID {
+			// 交易记录已保存但用户余额未保存，只需更新余额
+			return nil
+		}
+	}
+	m.transactions[userID] = append(m.transactions[userID], transaction)
+	return nil
+}
+
+// recorded 检查账本中是否已有交易对应的分录，调用方需持有锁
+func (m *Manager) recorded(transaction Transaction) bool {
+	for _, entry := range m.ledger.Entries(ledger.UserAccount(transaction.UserID)) {
+		if entry.TransactionID == transaction.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// saveTransaction 保存交易记录和对应的用户，调用方需持有锁
+func (m *Manager) saveTransaction(transaction Transaction) error {
+	if err := m.store.Put(storage.CollectionTransactions, transaction.ID, transaction); err != nil {
+		return err
+	}
+	return m.saveUser(transaction.UserID)
+}
+
+// recordGameParticipation 追加进行中的游戏记录，调用方需持有锁
+func (m *Manager) recordGameParticipation(userID, gameID string, buyIn int) {
+	userGame := UserGame{
+		GameID:   gameID,
+		UserID:   userID,
+		JoinTime: time.Now(),
+		BuyIn:    buyIn,
+		Status:   UserGameActive,
+	}
+	m.userGames[userID] = append(m.userGames[userID], userGame)
+}
@@ -11,12 +11,15 @@ var (
 	ErrNotFound          = errors.New("not found")
 	ErrInvalidInput      = errors.New("invalid input")
 	ErrUnauthorized      = errors.New("unauthorized")
+	ErrInvalidToken      = errors.New("invalid or expired token")
+	ErrForbidden         = errors.New("forbidden")
 	ErrInsufficientFunds = errors.New("insufficient funds")
 )
 
 // 游戏状态相关错误
 var (
 	ErrGameNotFound     = errors.New("game not found")
+	ErrGameExists       = errors.New("game already exists")
 	ErrGameFull         = errors.New("game is full")
 	ErrGameInProgress   = errors.New("game is already in progress")
 	ErrGameFinished     = errors.New("game is already finished")
@@ -26,11 +29,19 @@ var (
 
 // 玩家相关错误
 var (
-	ErrPlayerNotFound = errors.New("player not found")
-	ErrPlayerExists   = errors.New("player already exists")
-	ErrNotYourTurn    = errors.New("not your turn")
-	ErrAlreadyRolled  = errors.New("already rolled dice this turn")
-	ErrInPrison       = errors.New("player is in prison")
+	ErrPlayerNotFound  = errors.New("player not found")
+	ErrPlayerExists    = errors.New("player already exists")
+	ErrNotYourTurn     = errors.New("not your turn")
+	ErrAlreadyRolled   = errors.New("already rolled dice this turn")
+	ErrInPrison        = errors.New("player is in prison")
+	ErrNotInPrison     = errors.New("player is not in prison")
+	ErrNoPrisonCard    = errors.New("player has no get-out-of-prison card")
+	ErrOutstandingDebt = errors.New("player has outstanding debt")
+	ErrNoDebt          = errors.New("player has no outstanding debt")
+	ErrPlayerBankrupt  = errors.New("player is bankrupt")
+	ErrNotAtBank       = errors.New("player must land on or pass the bank this turn")
+	ErrLoanOutstanding = errors.New("player already has an outstanding loan")
+	ErrNoLoan          = errors.New("player has no outstanding loan")
 )
 
 // 地产相关错误
@@ -43,6 +54,30 @@ var (
 	ErrCannotAfford         = errors.New("cannot afford this action")
 	ErrPropertyNotOwned     = errors.New("property not owned")
 	ErrInvalidPropertyLevel = errors.New("invalid property level")
+	ErrPropertyMortgaged    = errors.New("property is mortgaged")
+	ErrPropertyNotMortgaged = errors.New("property is not mortgaged")
+	ErrPropertyHasUpgrades  = errors.New("property has upgrades, sell them first")
+	ErrGroupNotOwned        = errors.New("must own the full property group")
+	ErrUnevenBuild          = errors.New("must build evenly across the property group")
+)
+
+// 地图相关错误
+var (
+	ErrMapNotFound = errors.New("map not found")
+	ErrInvalidMap  = errors.New("invalid map definition")
+)
+
+// 交易相关错误
+var (
+	ErrTradeNotFound   = errors.New("trade not found")
+	ErrTradeNotPending = errors.New("trade is no longer pending")
+)
+
+// 拍卖相关错误
+var (
+	ErrNoAuction         = errors.New("no auction in progress")
+	ErrAuctionInProgress = errors.New("an auction is already in progress")
+	ErrBidTooLow         = errors.New("bid must exceed the highest bid")
 )
 
 // 游戏操作相关错误
@@ -58,6 +93,9 @@ var (
 	ErrUserExists      = errors.New("user already exists")
 	ErrInvalidUserID   = errors.New("invalid user id")
 	ErrInvalidUsername = errors.New("invalid username")
+	ErrUserInGame      = errors.New("user has coins escrowed in an unfinished game")
+	ErrInvalidPassword = errors.New("password must be 8 to 72 bytes")
+	ErrInvalidRole     = errors.New("invalid role")
 )
 
 // 错误检查函数
@@ -65,6 +103,8 @@ func IsNotFound(err error) bool {
 	return errors.Is(err, ErrNotFound) ||
 		errors.Is(err, ErrGameNotFound) ||
 		errors.Is(err, ErrPlayerNotFound) ||
+		errors.Is(err, ErrTradeNotFound) ||
+		errors.Is(err, ErrMapNotFound) ||
 		errors.Is(err, ErrUserNotFound)
 }
 
@@ -73,30 +113,70 @@ func IsInvalidInput(err error) bool {
 		errors.Is(err, ErrInvalidPosition) ||
 		errors.Is(err, ErrInvalidAction) ||
 		errors.Is(err, ErrInvalidUserID) ||
-		errors.Is(err, ErrInvalidUsername)
+		errors.Is(err, ErrInvalidUsername) ||
+		errors.Is(err, ErrInvalidPassword) ||
+		errors.Is(err, ErrInvalidRole) ||
+		errors.Is(err, ErrInvalidMap) ||
+		errors.Is(err, ErrBidTooLow)
 }
 
 func IsUnauthorized(err error) bool {
 	return errors.Is(err, ErrUnauthorized) ||
+		errors.Is(err, ErrInvalidToken) ||
 		errors.Is(err, ErrNotYourTurn) ||
 		errors.Is(err, ErrNotOwner)
 }
 
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrGameExists) ||
+		errors.Is(err, ErrPlayerExists) ||
+		errors.Is(err, ErrUserExists)
+}
+
 func IsInsufficientFunds(err error) bool {
 	return errors.Is(err, ErrInsufficientFunds) ||
-		errors.Is(err, ErrCannotAfford)
+		errors.Is(err, ErrCannotAfford) ||
+		errors.Is(err, ErrOutstandingDebt)
 }
 
 func IsGameStateError(err error) bool {
 	return errors.Is(err, ErrGameInProgress) ||
 		errors.Is(err, ErrGameFinished) ||
-		errors.Is(err, ErrInvalidGameState)
+		errors.Is(err, ErrInvalidGameState) ||
+		errors.Is(err, ErrGameFull) ||
+		errors.Is(err, ErrNotEnoughPlayers) ||
+		errors.Is(err, ErrAlreadyRolled) ||
+		errors.Is(err, ErrInPrison) ||
+		errors.Is(err, ErrTimeout) ||
+		errors.Is(err, ErrNoDebt) ||
+		errors.Is(err, ErrTradeNotPending) ||
+		errors.Is(err, ErrNoAuction) ||
+		errors.Is(err, ErrAuctionInProgress) ||
+		errors.Is(err, ErrActionNotAllowed) ||
+		errors.Is(err, ErrPlayerBankrupt) ||
+		errors.Is(err, ErrNotAtBank) ||
+		errors.Is(err, ErrLoanOutstanding) ||
+		errors.Is(err, ErrNoLoan) ||
+		errors.Is(err, ErrNotInPrison) ||
+		errors.Is(err, ErrNoPrisonCard) ||
+		errors.Is(err, ErrUserInGame)
 }
 
 func IsPropertyError(err error) bool {
 	return errors.Is(err, ErrNotProperty) ||
 		errors.Is(err, ErrPropertyOwned) ||
-		errors.Is(err, ErrMaxLevel)
+		errors.Is(err, ErrPropertyNotOwned) ||
+		errors.Is(err, ErrInvalidPropertyLevel) ||
+		errors.Is(err, ErrMaxLevel) ||
+		errors.Is(err, ErrPropertyMortgaged) ||
+		errors.Is(err, ErrPropertyNotMortgaged) ||
+		errors.Is(err, ErrPropertyHasUpgrades) ||
+		errors.Is(err, ErrGroupNotOwned) ||
+		errors.Is(err, ErrUnevenBuild)
 }
 
 // ErrorResponse 用于API响应的错误信息结构
@@ -122,6 +202,10 @@ func getErrorCode(err error) string {
 		return "INVALID_INPUT"
 	case IsUnauthorized(err):
 		return "UNAUTHORIZED"
+	case IsForbidden(err):
+		return "FORBIDDEN"
+	case IsConflict(err):
+		return "CONFLICT"
 	case IsInsufficientFunds(err):
 		return "INSUFFICIENT_FUNDS"
 	case IsGameStateError(err):
@@ -142,10 +226,16 @@ func HTTPStatusFromError(err error) int {
 		return http.StatusBadRequest
 	case IsUnauthorized(err):
 		return http.StatusUnauthorized
+	case IsForbidden(err):
+		return http.StatusForbidden
+	case IsConflict(err):
+		return http.StatusConflict
 	case IsInsufficientFunds(err):
 		return http.StatusPaymentRequired
 	case IsGameStateError(err):
 		return http.StatusConflict
+	case IsPropertyError(err):
+		return http.StatusConflict
 	default:
 		return http.StatusInternalServerError
 	}
@@ -0,0 +1,45 @@
+// pkg/utils/errors_test.go
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestErrorMapping(t *testing.T) {
+	tests := []struct {
+		err    error
+		code   string
+		status int
+	}{
+		{ErrGameFull, "GAME_STATE_ERROR", http.StatusConflict},
+		{ErrNotEnoughPlayers, "GAME_STATE_ERROR", http.StatusConflict},
+		{ErrAlreadyRolled, "GAME_STATE_ERROR", http.StatusConflict},
+		{ErrInPrison, "GAME_STATE_ERROR", http.StatusConflict},
+		{ErrNotInPrison, "GAME_STATE_ERROR", http.StatusConflict},
+		{ErrNoPrisonCard, "GAME_STATE_ERROR", http.StatusConflict},
+		{ErrTimeout, "GAME_STATE_ERROR", http.StatusConflict},
+		{ErrTradeNotPending, "GAME_STATE_ERROR", http.StatusConflict},
+		{ErrNoAuction, "GAME_STATE_ERROR", http.StatusConflict},
+		{ErrAuctionInProgress, "GAME_STATE_ERROR", http.StatusConflict},
+		{ErrGameExists, "CONFLICT", http.StatusConflict},
+		{ErrPlayerExists, "CONFLICT", http.StatusConflict},
+		{ErrUserExists, "CONFLICT", http.StatusConflict},
+		{ErrPropertyNotOwned, "PROPERTY_ERROR", http.StatusConflict},
+		{ErrInvalidPropertyLevel, "PROPERTY_ERROR", http.StatusConflict},
+		{ErrBidTooLow, "INVALID_INPUT", http.StatusBadRequest},
+		{fmt.Errorf("%w: no tiles", ErrInvalidMap), "INVALID_INPUT", http.StatusBadRequest},
+		{ErrMapNotFound, "NOT_FOUND", http.StatusNotFound},
+		{ErrTradeNotFound, "NOT_FOUND", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		if code := NewErrorResponse(tt.err).Code; code != tt.code {
+			t.Errorf("%v: got code %s, want %s", tt.err, code, tt.code)
+		}
+		if status := HTTPStatusFromError(tt.err); status != tt.status {
+			t.Errorf("%v: got status %d, want %d", tt.err, status, tt.status)
+		}
+	}
+}